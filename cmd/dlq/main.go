@@ -0,0 +1,177 @@
+// Command dlq lists the messages of the dead-letter topic and replays them to
+// the topic they failed on.
+//
+//	dlq                                  list every dead letter
+//	dlq -replay                          replay every dead letter
+//	dlq -replay -partition 0 -offset 12  replay one dead letter
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/internal/kafka"
+	"github.com/joho/godotenv"
+	"google.golang.org/protobuf/encoding/protojson"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	brokerAddr = "BROKER_ADDRESS"
+	topic      = "TOPIC"
+	partitions = "EVENT_PARTITIONER"
+)
+
+func main() {
+	_ = godotenv.Load()
+	broker := flag.String("broker", os.Getenv(brokerAddr), "Kafka broker address")
+	mainTopic := flag.String("topic", os.Getenv(topic), "topic whose dead letters are read")
+	replay := flag.Bool("replay", false, "send the dead letters back to their original topic")
+	partition := flag.Int("partition", -1, "only read this partition of the dead-letter topic")
+	offset := flag.Int64("offset", -1, "only read the message at this offset, requires -partition")
+	idle := flag.Duration("idle", 10*time.Second, "stop reading a partition after waiting this long for a message, "+
+		"as offsets removed by compaction or retention never arrive")
+	flag.Parse()
+	if *offset >= 0 && *partition < 0 {
+		log.Fatal("-offset requires -partition")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	client, err := sarama.NewClient([]string{*broker}, config)
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		log.Fatalf("failed to create consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	var producer *kafka.KafkaProducer
+	if *replay {
+		partitioner := kafka.PartitionerHash
+		if value := os.Getenv(partitions); value != "" {
+			if partitioner, err = kafka.ParsePartitioner(value); err != nil {
+				log.Fatalf("invalid %s: %v", partitions, err)
+			}
+		}
+		if producer, err = kafka.NewKafkaProducer(*broker, partitioner); err != nil {
+			log.Fatalf("failed to create producer: %v", err)
+		}
+		defer producer.Close()
+	}
+
+	deadLetterTopic := kafka.DeadLetterTopic(*mainTopic)
+	ids, err := client.Partitions(deadLetterTopic)
+	if err != nil {
+		log.Fatalf("failed to list partitions of %s: %v", deadLetterTopic, err)
+	}
+	for _, id := range ids {
+		if *partition >= 0 && id != int32(*partition) {
+			continue
+		}
+		if err = readPartition(ctx, client, consumer, producer, deadLetterTopic, id, *offset, *idle); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// errIdle is returned when no message arrived on a partition for a while.
+var errIdle = errors.New("no message arrived")
+
+// readPartition prints the messages of partition that were there when it
+// started, replaying them when producer is not nil. It stops early once no
+// message arrived for idle, since messages of the range may have been removed
+// since the newest offset was read.
+func readPartition(ctx context.Context, client sarama.Client, consumer sarama.Consumer, producer *kafka.KafkaProducer,
+	topic string, partition int32, offset int64, idle time.Duration) error {
+	start, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return fmt.Errorf("failed to get oldest offset of partition %d: %v", partition, err)
+	}
+	end, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return fmt.Errorf("failed to get newest offset of partition %d: %v", partition, err)
+	}
+	if offset >= 0 {
+		start, end = max(start, offset), min(end, offset+1)
+	}
+	if start >= end {
+		return nil
+	}
+	pc, err := consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return fmt.Errorf("failed to read partition %d: %v", partition, err)
+	}
+	defer pc.Close()
+
+	for next := start; next < end; {
+		message, err := nextMessage(ctx, pc, idle)
+		if errors.Is(err, errIdle) {
+			fmt.Fprintf(os.Stderr, "partition %d: %v for %v, stopping before offset %d of %d\n", partition, err, idle, next, end)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read partition %d: %w", partition, err)
+		}
+		next = message.Offset + 1
+		printMessage(message)
+		if producer == nil {
+			continue
+		}
+		replayed, err := kafka.ReplayMessage(message)
+		if err != nil {
+			return err
+		}
+		if err = producer.SendMessage(replayed); err != nil {
+			return fmt.Errorf("failed to replay message %d/%d: %v", partition, message.Offset, err)
+		}
+		fmt.Printf("replayed to %s\n", replayed.Topic)
+	}
+	return nil
+}
+
+// nextMessage waits for the next message of pc, for at most idle.
+func nextMessage(ctx context.Context, pc sarama.PartitionConsumer, idle time.Duration) (*sarama.ConsumerMessage, error) {
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+	select {
+	case message, ok := <-pc.Messages():
+		if !ok {
+			return nil, errors.New("partition consumer closed")
+		}
+		return message, nil
+	case err, ok := <-pc.Errors():
+		if !ok {
+			return nil, errors.New("partition consumer closed")
+		}
+		return nil, err
+	case <-timer.C:
+		return nil, errIdle
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func printMessage(message *sarama.ConsumerMessage) {
+	fmt.Printf("%s/%d/%d key=%s\n", message.Topic, message.Partition, message.Offset, message.Key)
+	for _, header := range message.Headers {
+		fmt.Printf("  %s: %s\n", header.Key, header.Value)
+	}
+	event, err := kafka.DecodeEvent(message)
+	if err != nil {
+		fmt.Printf("  undecodable: %v\n", err)
+		return
+	}
+	fmt.Printf("  %s\n", protojson.Format(event))
+}
@@ -3,35 +3,95 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/NRKA/gRPC-Server/internal/db"
 	"github.com/NRKA/gRPC-Server/internal/handlers"
+	"github.com/NRKA/gRPC-Server/internal/health"
+	"github.com/NRKA/gRPC-Server/internal/interceptors"
 	"github.com/NRKA/gRPC-Server/internal/kafka"
+	"github.com/NRKA/gRPC-Server/internal/metrics"
+	"github.com/NRKA/gRPC-Server/internal/purger"
 	"github.com/NRKA/gRPC-Server/internal/repository/postgresql"
+	"github.com/NRKA/gRPC-Server/internal/watcher"
+	"github.com/NRKA/gRPC-Server/pkg/events"
 	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
 	"github.com/NRKA/gRPC-Server/pkg/logger"
 	"github.com/joho/godotenv"
 	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/uber/jaeger-client-go/config"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	port       = "PORT"
-	dbHost     = "DB_HOST"
-	dbPort     = "DB_PORT"
-	dbUser     = "DB_USER"
-	dbPassword = "DB_PASSWORD"
-	dbName     = "DB_NAME"
-	brokerAddr = "BROKER_ADDRESS"
-	topic      = "TOPIC"
+	port        = "PORT"
+	adminPort   = "ADMIN_PORT"
+	gatewayPort = "GATEWAY_PORT"
+	dbHost      = "DB_HOST"
+	dbPort      = "DB_PORT"
+	dbUser      = "DB_USER"
+	dbPassword  = "DB_PASSWORD"
+	dbName      = "DB_NAME"
+	brokerAddr  = "BROKER_ADDRESS"
+	topic       = "TOPIC"
+	pageSecret  = "PAGE_TOKEN_SECRET"
+	retention   = "DELETED_RETENTION"
+	encoding    = "EVENT_ENCODING"
+	partitions  = "EVENT_PARTITIONER"
+
+	producerMode  = "EVENT_PRODUCER"
+	linger        = "EVENT_LINGER"
+	batchBytes    = "EVENT_BATCH_BYTES"
+	compression   = "EVENT_COMPRESSION"
+	idempotent    = "EVENT_IDEMPOTENT"
+	acks          = "EVENT_ACKS"
+	bufferSize    = "EVENT_BUFFER_SIZE"
+	backpressure  = "EVENT_BACKPRESSURE"
+	flushTimeout  = "EVENT_FLUSH_TIMEOUT"
+	asyncProducer = "async"
+	spoolDir      = "EVENT_SPOOL_DIR"
+
+	consumerGroup  = "CONSUMER_GROUP"
+	initialOffset  = "CONSUMER_INITIAL_OFFSET"
+	rebalance      = "CONSUMER_REBALANCE"
+	defaultGroupID = "gRPC-Server"
+	retryDelays    = "CONSUMER_RETRY_DELAYS"
+	failurePolicy  = "CONSUMER_FAILURE_POLICY"
+	drainTimeout   = "CONSUMER_DRAIN_TIMEOUT"
+)
+
+// Timeouts of the HTTP servers for reading a request, so slow clients cannot
+// hold connections open.
+const (
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 30 * time.Second
 )
 
+// shutdownTimeout is how long in-flight RPCs get to finish on shutdown before
+// the remaining ones, such as open watch streams, are cancelled.
+const shutdownTimeout = 10 * time.Second
+
+// eventProducer is implemented by kafka.KafkaProducer and
+// kafka.AsyncKafkaProducer.
+type eventProducer interface {
+	kafka.KafkaInterface
+	SetEncoding(encoding kafka.Encoding)
+	Close() error
+}
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Fatalf("Error loading .env file: %v", err)
@@ -40,21 +100,88 @@ func main() {
 	defer stop()
 	brokerAddress := os.Getenv(brokerAddr)
 
-	producer, err := kafka.NewKafkaProducer(brokerAddress)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	kafkaMetrics := metrics.NewKafkaMetrics(registry)
+	rpcMetrics := metrics.NewRPCMetrics(registry)
+
+	partitioner := kafka.PartitionerHash
+	if value := os.Getenv(partitions); value != "" {
+		parsed, err := kafka.ParsePartitioner(value)
+		if err != nil {
+			logger.Fatalf(ctx, "invalid %s: %v", partitions, err)
+		}
+		partitioner = parsed
+	}
+	producer, err := kafka.NewKafkaProducer(brokerAddress, partitioner)
 	if err != nil {
 		logger.Fatalf(ctx, "failed to create producer: %v", err)
 	}
+	// The outbox relay always waits for Kafka, so an event is marked delivered
+	// only once it was. Events sent inline by the handlers may use the async
+	// producer instead.
+	var handlerProducer eventProducer = producer
+	if os.Getenv(producerMode) == asyncProducer && os.Getenv(spoolDir) != "" {
+		// The async producer reports no delivery failures to its callers, so
+		// nothing would ever be spooled.
+		logger.Fatalf(ctx, "%s=%s cannot be combined with %s", producerMode, asyncProducer, spoolDir)
+	}
+	if os.Getenv(producerMode) == asyncProducer {
+		asyncConfig, err := asyncProducerConfig(partitioner)
+		if err != nil {
+			logger.Fatalf(ctx, "invalid async producer config: %v", err)
+		}
+		asyncEvents, err := kafka.NewAsyncKafkaProducer(brokerAddress, asyncConfig)
+		if err != nil {
+			// The sync producer connects once Kafka is back.
+			logger.Errorf(ctx, "failed to create async producer, using the sync one: %v", err)
+		} else {
+			handlerProducer = asyncEvents
+			// Flush the events buffered by the async producer on shutdown.
+			defer func() {
+				err := asyncEvents.Close()
+				if err != nil {
+					logger.Errorf(ctx, "Failed to flush async producer: %v", err)
+				}
+			}()
+		}
+	}
+	if value := os.Getenv(encoding); value != "" {
+		eventEncoding, err := kafka.ParseEncoding(value)
+		if err != nil {
+			logger.Fatalf(ctx, "invalid %s: %v", encoding, err)
+		}
+		producer.SetEncoding(eventEncoding)
+		handlerProducer.SetEncoding(eventEncoding)
+	}
 	defer func() {
 		err := producer.Close()
 		if err != nil {
 			logger.Errorf(ctx, "Failed to close producer: %v", err)
 		}
 	}()
+	// Events the handlers fail to send are spooled to disk and replayed once
+	// Kafka is back.
+	handlerEvents := kafkaMetrics.Producer("handlers", handlerProducer)
+	if dir := os.Getenv(spoolDir); dir != "" {
+		spool, err := kafka.OpenSpool(dir)
+		if err != nil {
+			logger.Fatalf(ctx, "failed to open event spool: %v", err)
+		}
+		resilient := kafka.NewResilientProducer(handlerEvents, spool, kafka.DefaultResilienceConfig())
+		go resilient.Run(ctx)
+		handlerEvents = resilient
+	}
 
-	consumer, err := kafka.NewKafkaConsumer(brokerAddress)
+	consumerConfig, err := kafkaConsumerConfig()
+	if err != nil {
+		logger.Fatalf(ctx, "invalid consumer config: %v", err)
+	}
+	consumer, err := kafka.NewKafkaConsumer(brokerAddress, consumerConfig)
 	if err != nil {
 		logger.Fatalf(ctx, "failed to create consumer: %v", err)
 	}
+	consumer.SetLagObserver(kafkaMetrics)
 	defer func() {
 		err := consumer.Close()
 		if err != nil {
@@ -77,6 +204,12 @@ func main() {
 	}
 	defer database.GetPool().Close()
 	articleRepo := postgresql.NewArticleRepo(database)
+	articleCollector := metrics.NewArticleCollector(articleRepo.Count)
+	go articleCollector.Run(ctx)
+	registry.MustRegister(
+		metrics.NewPoolCollector(func() metrics.PoolStats { return database.GetPool().Stat() }),
+		articleCollector,
+	)
 
 	zapLogger, err := zap.NewProduction()
 	if err != nil {
@@ -106,20 +239,118 @@ func main() {
 	defer closer.Close()
 
 	opentracing.SetGlobalTracer(tracer)
-	server := grpc.NewServer()
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.UnaryLogging, interceptors.UnaryTracing, rpcMetrics.UnaryInterceptor, interceptors.UnaryRecovery, handlers.PrincipalInterceptor),
+		grpc.ChainStreamInterceptor(interceptors.StreamLogging, interceptors.StreamTracing, rpcMetrics.StreamInterceptor, interceptors.StreamRecovery, handlers.StreamPrincipalInterceptor),
+	)
+
+	watchers := watcher.NewHub(watcher.DefaultHistorySize)
+	relay := kafka.NewOutboxRelay(postgresql.NewOutboxRepo(database), kafkaMetrics.Producer("relay", producer), os.Getenv(topic))
+	go relay.Run(ctx)
+	// Watchers are fed from the topic rather than by the relay, which only
+	// sees the events it claimed itself, so every server notifies its watchers
+	// of all the changes.
+	changes := kafka.NewRegistry(kafka.DefaultRetryPolicy)
+	err = changes.Register("watchers", kafka.EventHandlerFunc(func(_ context.Context, event *events.ArticleEvent) error {
+		change, err := watcher.ChangeFromEvent(event)
+		if err != nil {
+			return err
+		}
+		watchers.Publish(change)
+		return nil
+	}), watcher.EventTypes...)
+	if err != nil {
+		logger.Fatalf(ctx, "failed to register event handler: %v", err)
+	}
+	registry.MustRegister(metrics.NewHandlerCollector("watchers", changes.Stats))
+	go kafka.NewBroadcastConsumer(brokerAddress).Run(ctx, os.Getenv(topic), changes)
 
-	service := handlers.NewGrpcArticleHandler(articleRepo, producer)
+	deletedRetention := purger.DefaultRetention
+	if value := os.Getenv(retention); value != "" {
+		deletedRetention, err = time.ParseDuration(value)
+		if err != nil {
+			logger.Fatalf(ctx, "invalid %s: %v", retention, err)
+		}
+	}
+	go purger.New(articleRepo, deletedRetention).Run(ctx)
+
+	service := handlers.NewGrpcArticleHandler(articleRepo, handlerEvents)
+	// The replicas must share the key, or page tokens break whenever a client
+	// is balanced to another one.
+	secret := os.Getenv(pageSecret)
+	if secret == "" {
+		logger.Fatalf(ctx, "%s must be set", pageSecret)
+	}
+	service.SetPageTokenSecret([]byte(secret))
+	service.SetWatchers(watchers)
 	grpcServer.RegisterArticleServiceServer(server, service)
 
-	go func() {
-		err := consumer.Consume(ctx, os.Getenv(topic))
+	monitor := health.NewMonitor(health.DefaultInterval, grpcServer.ArticleService_ServiceDesc.ServiceName)
+	monitor.AddCheck("postgres", health.CheckerFunc(database.Ping))
+	monitor.AddCheck("kafka-producer", producer)
+	monitor.AddCheck("kafka-consumer", consumer)
+	grpc_health_v1.RegisterHealthServer(server, monitor.Server())
+	go monitor.Run(ctx)
+	if addr := os.Getenv(adminPort); addr != "" {
+		admin := http.NewServeMux()
+		admin.Handle("/", monitor.Handler())
+		admin.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		adminServer := &http.Server{Addr: addr, Handler: admin, ReadHeaderTimeout: readHeaderTimeout, ReadTimeout: readTimeout}
+		go func() {
+			logger.Infof(ctx, "admin server listening on %q", addr)
+			err := adminServer.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorf(ctx, "failed to serve admin endpoints: %v", err)
+			}
+		}()
+		defer adminServer.Close()
+	}
+	if addr := os.Getenv(gatewayPort); addr != "" {
+		gatewayServer, closeGateway, err := newGatewayServer(addr, port)
 		if err != nil {
-			logger.Errorf(ctx, "failed to consume: %v", err)
-			return
+			logger.Fatalf(ctx, "failed to create gateway: %v", err)
 		}
+		go func() {
+			logger.Infof(ctx, "gateway listening on %q", addr)
+			err := gatewayServer.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorf(ctx, "failed to serve gateway: %v", err)
+			}
+		}()
+		defer closeGateway()
+	}
 
-		server.Stop()
-		return
+	eventHandlers := kafka.NewRegistry(kafka.DefaultRetryPolicy)
+	if err = eventHandlers.Register("audit-log", kafka.LogEvent); err != nil {
+		logger.Fatalf(ctx, "failed to register event handler: %v", err)
+	}
+	registry.MustRegister(metrics.NewHandlerCollector("events", eventHandlers.Stats))
+	deadLetterConfig, err := kafkaDeadLetterConfig()
+	if err != nil {
+		logger.Fatalf(ctx, "invalid dead-letter config: %v", err)
+	}
+	deadLetters := kafka.NewDeadLetterHandler(eventHandlers, producer, os.Getenv(topic), deadLetterConfig)
+	go func() {
+		// With the fail-fast policy a failed consumer shuts the server down,
+		// otherwise the consumer restarts on its own and only the signal does.
+		err := consumer.Run(ctx, deadLetters.Topics(), deadLetters)
+		if err != nil {
+			logger.Errorf(ctx, "failed to consume, shutting down: %v", err)
+			stop()
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			server.Stop()
+		}
 	}()
 
 	logger.Infof(ctx, "server listening on %q", port)
@@ -132,3 +363,129 @@ func main() {
 		logger.Fatalf(ctx, "failed to serve: %v", err)
 	}
 }
+
+// newGatewayServer creates the HTTP server of the REST/JSON gateway, which
+// calls the gRPC server listening on grpcAddr over loopback.
+func newGatewayServer(addr, grpcAddr string) (*http.Server, func(), error) {
+	if strings.HasPrefix(grpcAddr, ":") {
+		grpcAddr = "localhost" + grpcAddr
+	}
+	conn, err := grpc.Dial(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	gateway, err := handlers.NewGateway(grpcServer.NewArticleServiceClient(conn))
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	server := &http.Server{Addr: addr, Handler: gateway, ReadHeaderTimeout: readHeaderTimeout, ReadTimeout: readTimeout}
+	return server, func() {
+		_ = server.Close()
+		_ = conn.Close()
+	}, nil
+}
+
+// asyncProducerConfig overrides the defaults of the async producer with the
+// settings found in the environment.
+func asyncProducerConfig(partitioner kafka.Partitioner) (kafka.AsyncProducerConfig, error) {
+	config := kafka.DefaultAsyncProducerConfig()
+	config.Partitioner = partitioner
+	var err error
+	if value := os.Getenv(linger); value != "" {
+		if config.Linger, err = time.ParseDuration(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", linger, err)
+		}
+	}
+	if value := os.Getenv(batchBytes); value != "" {
+		if config.MaxBatchBytes, err = strconv.Atoi(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", batchBytes, err)
+		}
+	}
+	if value := os.Getenv(compression); value != "" {
+		if config.Compression, err = kafka.ParseCompression(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", compression, err)
+		}
+	}
+	if value := os.Getenv(idempotent); value != "" {
+		if config.Idempotent, err = strconv.ParseBool(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", idempotent, err)
+		}
+	}
+	if value := os.Getenv(acks); value != "" {
+		if config.Acks, err = kafka.ParseAcks(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", acks, err)
+		}
+	}
+	if value := os.Getenv(bufferSize); value != "" {
+		if config.BufferSize, err = strconv.Atoi(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", bufferSize, err)
+		}
+	}
+	if value := os.Getenv(backpressure); value != "" {
+		if config.Backpressure, err = kafka.ParseBackpressure(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", backpressure, err)
+		}
+	}
+	if value := os.Getenv(flushTimeout); value != "" {
+		if config.FlushTimeout, err = time.ParseDuration(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", flushTimeout, err)
+		}
+	}
+	return config, nil
+}
+
+// kafkaConsumerConfig overrides the defaults of the consumer with the settings
+// found in the environment.
+func kafkaConsumerConfig() (kafka.ConsumerConfig, error) {
+	groupID := os.Getenv(consumerGroup)
+	if groupID == "" {
+		groupID = defaultGroupID
+	}
+	config := kafka.DefaultConsumerConfig(groupID)
+	var err error
+	if value := os.Getenv(initialOffset); value != "" {
+		if config.InitialOffset, err = kafka.ParseInitialOffset(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", initialOffset, err)
+		}
+	}
+	if value := os.Getenv(rebalance); value != "" {
+		if config.Rebalance, err = kafka.ParseRebalanceStrategy(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", rebalance, err)
+		}
+	}
+	if value := os.Getenv(failurePolicy); value != "" {
+		if config.FailurePolicy, err = kafka.ParseFailurePolicy(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", failurePolicy, err)
+		}
+	}
+	if value := os.Getenv(drainTimeout); value != "" {
+		if config.DrainTimeout, err = time.ParseDuration(value); err != nil {
+			return config, fmt.Errorf("invalid %s: %w", drainTimeout, err)
+		}
+	}
+	return config, nil
+}
+
+// kafkaDeadLetterConfig reads the comma separated retry delays, such as
+// "1m,10m", from the environment. An empty list sends failed events to the
+// dead-letter topic right away.
+func kafkaDeadLetterConfig() (kafka.DeadLetterConfig, error) {
+	config := kafka.DefaultDeadLetterConfig()
+	value, ok := os.LookupEnv(retryDelays)
+	if !ok {
+		return config, nil
+	}
+	config.RetryDelays = nil
+	for _, delay := range strings.Split(value, ",") {
+		if delay = strings.TrimSpace(delay); delay == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(delay)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", retryDelays, err)
+		}
+		config.RetryDelays = append(config.RetryDelays, parsed)
+	}
+	return config, nil
+}
@@ -43,18 +43,40 @@ func (db Database) GetPool() *pgxpool.Pool {
 	return db.cluster
 }
 
+// Ping checks that a connection to the database can be acquired and used.
+func (db Database) Ping(ctx context.Context) error {
+	return db.cluster.Ping(ctx)
+}
+
 func (db Database) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Get(ctx, db.cluster, dest, query, args...)
+	return pgxscan.Get(ctx, db.conn(ctx), dest, query, args...)
 }
 
 func (db Database) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Select(ctx, db.cluster, dest, query, args...)
+	return pgxscan.Select(ctx, db.conn(ctx), dest, query, args...)
 }
 
 func (db Database) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
-	return db.cluster.Exec(ctx, query, args...)
+	return db.conn(ctx).Exec(ctx, query, args...)
 }
 
 func (db Database) ExecQueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
-	return db.cluster.QueryRow(ctx, query, args...)
+	return db.conn(ctx).QueryRow(ctx, query, args...)
+}
+
+// BeginTx starts a transaction, or a savepoint within the transaction carried
+// by ctx. Options only apply to a top-level transaction.
+func (db Database) BeginTx(ctx context.Context, options pgx.TxOptions) (pgx.Tx, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.Begin(ctx)
+	}
+	return db.cluster.BeginTx(ctx, options)
+}
+
+func (db Database) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	return db.conn(ctx).SendBatch(ctx, batch)
+}
+
+func (db Database) CopyFrom(ctx context.Context, tableName pgx.Identifier, columns []string, rows pgx.CopyFromSource) (int64, error) {
+	return db.conn(ctx).CopyFrom(ctx, tableName, columns, rows)
 }
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX articles_rating_id_idx ON articles (rating, id);
+CREATE INDEX articles_created_at_id_idx ON articles (created_at, id);
+CREATE INDEX articles_name_id_idx ON articles (name, id);
+CREATE INDEX articles_name_pattern_idx ON articles (name text_pattern_ops);
+CREATE INDEX articles_name_trgm_idx ON articles USING GIN (name gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_name_trgm_idx;
+DROP INDEX articles_name_pattern_idx;
+DROP INDEX articles_name_id_idx;
+DROP INDEX articles_created_at_id_idx;
+DROP INDEX articles_rating_id_idx;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN name_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+CREATE INDEX articles_name_tsv_idx ON articles USING GIN (name_tsv);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_name_tsv_idx;
+ALTER TABLE articles DROP COLUMN name_tsv;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX articles_deleted_at_idx ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX articles_deleted_at_idx;
+ALTER TABLE articles DROP COLUMN deleted_at;
+-- +goose StatementEnd
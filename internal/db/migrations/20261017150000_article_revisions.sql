@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE article_revisions(
+    article_id BIGINT NOT NULL,
+    version BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    rating INT NOT NULL,
+    deleted BOOLEAN NOT NULL,
+    changed_fields TEXT[] NOT NULL,
+    principal TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (article_id, version)
+);
+INSERT INTO article_revisions(article_id,version,name,rating,deleted,changed_fields)
+SELECT id,version,name,rating,deleted_at IS NOT NULL,'{}' FROM articles;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE article_revisions;
+-- +goose StatementEnd
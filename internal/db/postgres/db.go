@@ -61,7 +61,7 @@ func (d *TDB) TearDown() {
 	}
 	defer db.Close()
 
-	if err = goose.Down(db, "../../db/migrations"); err != nil {
+	if err = goose.Reset(db, "../../db/migrations"); err != nil {
 		log.Fatalf("Error setting up the database migrations: %v", err)
 	}
 }
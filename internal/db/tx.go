@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	maxTxAttempts  = 3
+	txRetryBackoff = 10 * time.Millisecond
+
+	serializationFailure = "40001"
+)
+
+type txKey struct{}
+
+// querier is the part of the pool that a transaction provides as well.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// conn returns the transaction carried by ctx, or the pool outside of one.
+func (db Database) conn(ctx context.Context) querier {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db.cluster
+}
+
+// RunInTx runs fn in a transaction with the given options. Every Database
+// method called with the context passed to fn runs in that transaction. The
+// transaction is committed if fn returns nil and rolled back otherwise.
+//
+// A serialization failure rolls the transaction back and runs fn again, up to
+// maxTxAttempts times in total, so fn must not have side effects outside of
+// the database. When ctx already carries a transaction fn joins it, options are
+// ignored and retrying is left to the outermost RunInTx.
+func (db Database) RunInTx(ctx context.Context, options pgx.TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = db.runInTx(ctx, options, fn)
+		if !isSerializationFailure(err) || attempt == maxTxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txRetryBackoff):
+		}
+	}
+	return err
+}
+
+func (db Database) runInTx(ctx context.Context, options pgx.TxOptions, fn func(ctx context.Context) error) error {
+	tx, err := db.cluster.BeginTx(ctx, options)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailure
+}
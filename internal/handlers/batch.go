@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxBatchSize = 1000
+
+var batchModes = map[grpcServer.BatchMode]repository.BatchMode{
+	grpcServer.BatchMode_BATCH_MODE_UNSPECIFIED: repository.BatchAtomic,
+	grpcServer.BatchMode_BATCH_MODE_ATOMIC:      repository.BatchAtomic,
+	grpcServer.BatchMode_BATCH_MODE_BEST_EFFORT: repository.BatchBestEffort,
+}
+
+func DataConvertationBatchMode(size int, mode grpcServer.BatchMode) (repository.BatchMode, error) {
+	if size == 0 {
+		return 0, errors.New("batch is empty")
+	}
+	if size > maxBatchSize {
+		return 0, fmt.Errorf("batch has %d items, at most %d are allowed", size, maxBatchSize)
+	}
+	batchMode, ok := batchModes[mode]
+	if !ok {
+		return 0, fmt.Errorf("unknown batch mode %d", mode)
+	}
+	return batchMode, nil
+}
+
+// batchPlan tracks which request items are handed to the repository and
+// collects the per-item results in request order.
+type batchPlan struct {
+	mode      repository.BatchMode
+	results   []*grpcServer.BatchItemResult
+	positions []int
+	rejected  bool
+}
+
+func newBatchPlan(size int, mode repository.BatchMode) *batchPlan {
+	return &batchPlan{
+		mode:      mode,
+		results:   make([]*grpcServer.BatchItemResult, size),
+		positions: make([]int, 0, size),
+	}
+}
+
+func (plan *batchPlan) accept(i int) {
+	plan.positions = append(plan.positions, i)
+}
+
+func (plan *batchPlan) reject(i int, id int64, code codes.Code, message string) {
+	plan.rejected = true
+	plan.results[i] = &grpcServer.BatchItemResult{Id: id, Code: int32(code), Message: message}
+}
+
+// abortAtomic reports whether the repository call must be skipped because an
+// atomic batch already contains a rejected item.
+func (plan *batchPlan) abortAtomic() bool {
+	if plan.mode != repository.BatchAtomic || !plan.rejected {
+		return false
+	}
+	for _, i := range plan.positions {
+		plan.results[i] = &grpcServer.BatchItemResult{
+			Code:    int32(codes.Aborted),
+			Message: repository.ErrBatchAborted.Error(),
+		}
+	}
+	return true
+}
+
+// record stores the repository result of the k-th accepted item.
+func (plan *batchPlan) record(k int, result repository.BatchItemResult, errPrefix string) {
+	i := plan.positions[k]
+	item := &grpcServer.BatchItemResult{Id: result.ID, Code: int32(codes.OK)}
+	switch {
+	case result.Err == nil:
+	case errors.Is(result.Err, repository.ErrArticalNotFound):
+		item.Code, item.Message = int32(codes.NotFound), result.Err.Error()
+	case errors.Is(result.Err, repository.ErrArticleDeleted):
+		item.Code, item.Message = int32(codes.FailedPrecondition), result.Err.Error()
+	case errors.Is(result.Err, repository.ErrBatchAborted), errors.Is(result.Err, repository.ErrVersionConflict):
+		item.Code, item.Message = int32(codes.Aborted), result.Err.Error()
+	default:
+		item.Code, item.Message = int32(codes.Internal), errPrefix+result.Err.Error()
+	}
+	plan.results[i] = item
+}
+
+func (handler *GrpcArticleHandler) BatchCreateArticles(ctx context.Context, request *grpcServer.BatchCreateArticlesRequest) (*grpcServer.BatchArticlesResponse, error) {
+	mode, err := DataConvertationBatchMode(len(request.Articles), request.Mode)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidBatch+err.Error())
+	}
+
+	plan := newBatchPlan(len(request.Articles), mode)
+	articles := make([]repository.Article, 0, len(request.Articles))
+	for i, article := range request.Articles {
+		articleData := DataConvertationСreate(article)
+		if articleData.Name == "" || articleData.Rating < 1 {
+			plan.reject(i, 0, codes.InvalidArgument, errInvalidData)
+			continue
+		}
+		plan.accept(i)
+		articles = append(articles, articleData)
+	}
+	if plan.abortAtomic() || len(articles) == 0 {
+		return &grpcServer.BatchArticlesResponse{Results: plan.results}, nil
+	}
+
+	results, err := handler.repo.BatchCreate(ctx, articles, mode)
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleCreate+err.Error())
+	}
+
+	for k, result := range results {
+		plan.record(k, result, errArticleCreate)
+	}
+
+	return &grpcServer.BatchArticlesResponse{Results: plan.results}, nil
+}
+
+func (handler *GrpcArticleHandler) BatchUpdateArticles(ctx context.Context, request *grpcServer.BatchUpdateArticlesRequest) (*grpcServer.BatchArticlesResponse, error) {
+	mode, err := DataConvertationBatchMode(len(request.Articles), request.Mode)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidBatch+err.Error())
+	}
+
+	plan := newBatchPlan(len(request.Articles), mode)
+	updates := make([]repository.ArticleUpdate, 0, len(request.Articles))
+	for i, article := range request.Articles {
+		update, err := DataConvertationUpdate(article)
+		if err != nil {
+			plan.reject(i, article.Id, codes.InvalidArgument, err.Error())
+			continue
+		}
+		plan.accept(i)
+		updates = append(updates, update)
+	}
+	if plan.abortAtomic() || len(updates) == 0 {
+		return &grpcServer.BatchArticlesResponse{Results: plan.results}, nil
+	}
+
+	results, err := handler.repo.BatchUpdate(ctx, updates, mode)
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleUpdate+err.Error())
+	}
+
+	for k, result := range results {
+		plan.record(k, result, errArticleUpdate)
+	}
+
+	return &grpcServer.BatchArticlesResponse{Results: plan.results}, nil
+}
+
+func (handler *GrpcArticleHandler) BatchDeleteArticles(ctx context.Context, request *grpcServer.BatchDeleteArticlesRequest) (*grpcServer.BatchArticlesResponse, error) {
+	mode, err := DataConvertationBatchMode(len(request.Ids), request.Mode)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidBatch+err.Error())
+	}
+
+	plan := newBatchPlan(len(request.Ids), mode)
+	for i := range request.Ids {
+		plan.accept(i)
+	}
+
+	results, err := handler.repo.BatchDelete(ctx, request.Ids, mode)
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleDelete+err.Error())
+	}
+
+	for k, result := range results {
+		plan.record(k, result, errArticleDelete)
+	}
+
+	return &grpcServer.BatchArticlesResponse{Results: plan.results}, nil
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mock_kafka_interface "github.com/NRKA/gRPC-Server/internal/kafka/mocks"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	mock_repository "github.com/NRKA/gRPC-Server/internal/repository/mocks"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func batchCodes(response *grpcServer.BatchArticlesResponse) []codes.Code {
+	result := make([]codes.Code, 0, len(response.Results))
+	for _, item := range response.Results {
+		result = append(result, codes.Code(item.Code))
+	}
+	return result
+}
+
+func TestArticleHandler_BatchCreate(t *testing.T) {
+	t.Parallel()
+
+	valid := &grpcServer.CreateArticleRequest{Name: "name", Rating: 10}
+	invalid := &grpcServer.CreateArticleRequest{Name: "", Rating: 10}
+
+	testCases := []struct {
+		name            string
+		request         *grpcServer.BatchCreateArticlesRequest
+		expectedRepo    []repository.Article
+		expectedMode    repository.BatchMode
+		mockReturnValue []repository.BatchItemResult
+		mockError       error
+		expectedCode    codes.Code
+		expectedItems   []codes.Code
+		expectedIDs     []int64
+	}{{
+		name:            "atomic success",
+		request:         &grpcServer.BatchCreateArticlesRequest{Articles: []*grpcServer.CreateArticleRequest{valid, valid}},
+		expectedRepo:    []repository.Article{{Name: "name", Rating: 10}, {Name: "name", Rating: 10}},
+		expectedMode:    repository.BatchAtomic,
+		mockReturnValue: []repository.BatchItemResult{{ID: 1}, {ID: 2}},
+		expectedCode:    codes.OK,
+		expectedItems:   []codes.Code{codes.OK, codes.OK},
+		expectedIDs:     []int64{1, 2},
+	}, {
+		name: "atomic with invalid item",
+		request: &grpcServer.BatchCreateArticlesRequest{
+			Articles: []*grpcServer.CreateArticleRequest{valid, invalid},
+			Mode:     grpcServer.BatchMode_BATCH_MODE_ATOMIC,
+		},
+		expectedCode:  codes.OK,
+		expectedItems: []codes.Code{codes.Aborted, codes.InvalidArgument},
+		expectedIDs:   []int64{0, 0},
+	}, {
+		name: "best effort with invalid item",
+		request: &grpcServer.BatchCreateArticlesRequest{
+			Articles: []*grpcServer.CreateArticleRequest{invalid, valid},
+			Mode:     grpcServer.BatchMode_BATCH_MODE_BEST_EFFORT,
+		},
+		expectedRepo:    []repository.Article{{Name: "name", Rating: 10}},
+		expectedMode:    repository.BatchBestEffort,
+		mockReturnValue: []repository.BatchItemResult{{ID: 7}},
+		expectedCode:    codes.OK,
+		expectedItems:   []codes.Code{codes.InvalidArgument, codes.OK},
+		expectedIDs:     []int64{0, 7},
+	}, {
+		name: "best effort with failed item",
+		request: &grpcServer.BatchCreateArticlesRequest{
+			Articles: []*grpcServer.CreateArticleRequest{valid, valid},
+			Mode:     grpcServer.BatchMode_BATCH_MODE_BEST_EFFORT,
+		},
+		expectedRepo:    []repository.Article{{Name: "name", Rating: 10}, {Name: "name", Rating: 10}},
+		expectedMode:    repository.BatchBestEffort,
+		mockReturnValue: []repository.BatchItemResult{{Err: fmt.Errorf("check constraint")}, {ID: 8}},
+		expectedCode:    codes.OK,
+		expectedItems:   []codes.Code{codes.Internal, codes.OK},
+		expectedIDs:     []int64{0, 8},
+	}, {
+		name:         "empty batch",
+		request:      &grpcServer.BatchCreateArticlesRequest{},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name: "unknown mode",
+		request: &grpcServer.BatchCreateArticlesRequest{
+			Articles: []*grpcServer.CreateArticleRequest{valid},
+			Mode:     42,
+		},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:         "internal server error",
+		request:      &grpcServer.BatchCreateArticlesRequest{Articles: []*grpcServer.CreateArticleRequest{valid}},
+		expectedRepo: []repository.Article{{Name: "name", Rating: 10}},
+		expectedMode: repository.BatchAtomic,
+		mockError:    fmt.Errorf("connection refused"),
+		expectedCode: codes.Internal,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			if tc.expectedRepo != nil {
+				mockRepo.EXPECT().BatchCreate(gomock.Any(), tc.expectedRepo, tc.expectedMode).Return(tc.mockReturnValue, tc.mockError)
+			}
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+
+			client := grpcServer.NewArticleServiceClient(conn)
+			response, err := client.BatchCreateArticles(context.Background(), tc.request)
+
+			if tc.expectedCode != codes.OK {
+				st, _ := status.FromError(err)
+				assert.Equal(t, tc.expectedCode, st.Code())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedItems, batchCodes(response))
+			ids := make([]int64, 0, len(response.Results))
+			for _, item := range response.Results {
+				ids = append(ids, item.Id)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestArticleHandler_BatchUpdate(t *testing.T) {
+	t.Parallel()
+
+	allFields := []repository.ArticleField{repository.FieldName, repository.FieldRating}
+	testCases := []struct {
+		name            string
+		request         *grpcServer.BatchUpdateArticlesRequest
+		expectedRepo    []repository.ArticleUpdate
+		mockReturnValue []repository.BatchItemResult
+		expectedItems   []codes.Code
+	}{{
+		name: "atomic with missing article",
+		request: &grpcServer.BatchUpdateArticlesRequest{Articles: []*grpcServer.UpdateArticleRequest{
+			{Id: 1, Name: "name", Rating: 10},
+			{Id: 2, Name: "name", Rating: 10},
+		}},
+		expectedRepo: []repository.ArticleUpdate{
+			{Article: repository.Article{ID: 1, Name: "name", Rating: 10}, Fields: allFields},
+			{Article: repository.Article{ID: 2, Name: "name", Rating: 10}, Fields: allFields},
+		},
+		mockReturnValue: []repository.BatchItemResult{
+			{ID: 1, Err: repository.ErrBatchAborted},
+			{ID: 2, Err: repository.ErrArticalNotFound},
+		},
+		expectedItems: []codes.Code{codes.Aborted, codes.NotFound},
+	}, {
+		name: "atomic with invalid item",
+		request: &grpcServer.BatchUpdateArticlesRequest{Articles: []*grpcServer.UpdateArticleRequest{
+			{Id: 1, Name: "name", Rating: 0},
+			{Id: 2, Name: "name", Rating: 10},
+		}},
+		expectedItems: []codes.Code{codes.InvalidArgument, codes.Aborted},
+	}, {
+		name: "success",
+		request: &grpcServer.BatchUpdateArticlesRequest{Articles: []*grpcServer.UpdateArticleRequest{
+			{Id: 1, Name: "name", Rating: 10},
+		}},
+		expectedRepo: []repository.ArticleUpdate{
+			{Article: repository.Article{ID: 1, Name: "name", Rating: 10}, Fields: allFields},
+		},
+		mockReturnValue: []repository.BatchItemResult{{ID: 1}},
+		expectedItems:   []codes.Code{codes.OK},
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			if tc.expectedRepo != nil {
+				mockRepo.EXPECT().BatchUpdate(gomock.Any(), tc.expectedRepo, repository.BatchAtomic).Return(tc.mockReturnValue, nil)
+			}
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+
+			client := grpcServer.NewArticleServiceClient(conn)
+			response, err := client.BatchUpdateArticles(context.Background(), tc.request)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedItems, batchCodes(response))
+		})
+	}
+}
+
+func TestArticleHandler_BatchDelete(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+	mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+	server := grpc.NewServer()
+	handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+	grpcServer.RegisterArticleServiceServer(server, handler)
+	mockRepo.EXPECT().BatchDelete(gomock.Any(), []int64{1, 2}, repository.BatchBestEffort).Return([]repository.BatchItemResult{
+		{ID: 1},
+		{ID: 2, Err: repository.ErrArticalNotFound},
+	}, nil)
+
+	conn, closeConnAndServer := setupGRPCConnection(t, server)
+	defer closeConnAndServer()
+
+	client := grpcServer.NewArticleServiceClient(conn)
+	response, err := client.BatchDeleteArticles(context.Background(), &grpcServer.BatchDeleteArticlesRequest{
+		Ids:  []int64{1, 2},
+		Mode: grpcServer.BatchMode_BATCH_MODE_BEST_EFFORT,
+	})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []codes.Code{codes.OK, codes.NotFound}, batchCodes(response))
+}
@@ -1,16 +1,32 @@
 package handlers
 
 var (
-	errReadReqBody     = "Failed to read request body:"
-	errParseJson       = "Failed to parse json:"
-	errCreateJson      = "Failed to create json:"
-	errQueryParamKey   = "Failed to find parameter in request"
-	errParseInt        = "Failed to parse int:"
-	errArticleNotFound = "Failed to find article:"
-	errArticleCreate   = "Failed to create article:"
-	errArticleGetById  = "Failed to get article by id:"
-	errArticleUpdate   = "Failed to update article:"
-	errArticleDelete   = "Failed to delete article:"
-	errInvalidData     = "invalid data"
-	errSendEvent       = "failed to send event"
+	errReadReqBody       = "Failed to read request body:"
+	errParseJson         = "Failed to parse json:"
+	errCreateJson        = "Failed to create json:"
+	errQueryParamKey     = "Failed to find parameter in request"
+	errParseInt          = "Failed to parse int:"
+	errArticleNotFound   = "Failed to find article:"
+	errArticleCreate     = "Failed to create article:"
+	errArticleGetById    = "Failed to get article by id:"
+	errArticleUpdate     = "Failed to update article:"
+	errArticleDelete     = "Failed to delete article:"
+	errArticleRestore    = "Failed to restore article:"
+	errArticlePurge      = "Failed to purge article:"
+	errArticleList       = "Failed to list articles:"
+	errArticleSearch     = "Failed to search articles:"
+	errArticleImport     = "Failed to import articles:"
+	errArticleHistory    = "Failed to get article history:"
+	errArticleRevision   = "Failed to get article revision:"
+	errInvalidData       = "invalid data"
+	errSendEvent         = "failed to send event"
+	errInvalidPageSize   = "invalid page size:"
+	errInvalidPage       = "invalid page token:"
+	errInvalidFilter     = "invalid filter:"
+	errInvalidSort       = "invalid sort:"
+	errEmptyQuery        = "search query must not be empty"
+	errWatchResume       = "cannot resume watch:"
+	errInvalidBatch      = "invalid batch:"
+	errInvalidUpdateMask = "invalid update mask:"
+	errWatchDropped      = "watch interrupted, resume from the last received sequence:"
 )
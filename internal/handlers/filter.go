@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/internal/watcher"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	errRatingRange  = errors.New("min_rating is greater than max_rating")
+	errCreatedRange = errors.New("created_after is not before created_before")
+)
+
+var sortFields = map[grpcServer.ArticleSortField]repository.SortField{
+	grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_UNSPECIFIED: repository.SortByID,
+	grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_ID:          repository.SortByID,
+	grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_RATING:      repository.SortByRating,
+	grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_CREATED_AT:  repository.SortByCreatedAt,
+	grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_NAME:        repository.SortByName,
+}
+
+var sortDirections = map[grpcServer.SortDirection]repository.SortDirection{
+	grpcServer.SortDirection_SORT_DIRECTION_UNSPECIFIED: repository.SortAscending,
+	grpcServer.SortDirection_SORT_DIRECTION_ASC:         repository.SortAscending,
+	grpcServer.SortDirection_SORT_DIRECTION_DESC:        repository.SortDescending,
+}
+
+func DataConvertationFilter(filter *grpcServer.ArticleFilter) (repository.ArticleFilter, error) {
+	var result repository.ArticleFilter
+	if filter == nil {
+		return result, nil
+	}
+	result.MinRating = filter.MinRating
+	result.MaxRating = filter.MaxRating
+	result.NamePrefix = filter.NamePrefix
+	result.NameContains = filter.NameContains
+	result.IncludeDeleted = filter.IncludeDeleted
+
+	var err error
+	if result.CreatedAfter, err = optionalTime(filter.CreatedAfter); err != nil {
+		return result, fmt.Errorf("created_after: %w", err)
+	}
+	if result.CreatedBefore, err = optionalTime(filter.CreatedBefore); err != nil {
+		return result, fmt.Errorf("created_before: %w", err)
+	}
+
+	if result.MinRating != nil && result.MaxRating != nil && *result.MinRating > *result.MaxRating {
+		return result, errRatingRange
+	}
+	if result.CreatedAfter != nil && result.CreatedBefore != nil && !result.CreatedAfter.Before(*result.CreatedBefore) {
+		return result, errCreatedRange
+	}
+	return result, nil
+}
+
+func DataConvertationSort(field grpcServer.ArticleSortField, direction grpcServer.SortDirection) (repository.ArticleSort, error) {
+	sortField, ok := sortFields[field]
+	if !ok {
+		return repository.ArticleSort{}, fmt.Errorf("unknown sort field %d", field)
+	}
+	sortDirection, ok := sortDirections[direction]
+	if !ok {
+		return repository.ArticleSort{}, fmt.Errorf("unknown sort direction %d", direction)
+	}
+	return repository.ArticleSort{Field: sortField, Direction: sortDirection}, nil
+}
+
+var changeTypes = map[watcher.ChangeType]grpcServer.ArticleChangeType{
+	watcher.Created:  grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_CREATED,
+	watcher.Updated:  grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_UPDATED,
+	watcher.Deleted:  grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_DELETED,
+	watcher.Restored: grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_RESTORED,
+	watcher.Purged:   grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_PURGED,
+}
+
+func DataConvertationWatchFilter(request *grpcServer.WatchArticlesRequest) (watcher.Filter, error) {
+	filter := watcher.Filter{
+		MinRating: request.MinRating,
+		MaxRating: request.MaxRating,
+	}
+	if filter.MinRating != nil && filter.MaxRating != nil && *filter.MinRating > *filter.MaxRating {
+		return filter, errRatingRange
+	}
+	if len(request.Ids) > 0 {
+		filter.IDs = make(map[int64]struct{}, len(request.Ids))
+		for _, id := range request.Ids {
+			filter.IDs[id] = struct{}{}
+		}
+	}
+	return filter, nil
+}
+
+func DataConvertationChange(change watcher.Change) *grpcServer.ArticleChange {
+	return &grpcServer.ArticleChange{
+		Sequence: change.Sequence,
+		Type:     changeTypes[change.Type],
+		Article:  DataConvertationArticle(change.Article),
+		Time:     timestamppb.New(change.Time),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optionalTime(timestamp *timestamppb.Timestamp) (*time.Time, error) {
+	if timestamp == nil {
+		return nil, nil
+	}
+	if err := timestamp.CheckValid(); err != nil {
+		return nil, err
+	}
+	t := timestamp.AsTime()
+	return &t, nil
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/NRKA/gRPC-Server/internal/interceptors"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	entityPath  = "/entity"
+	openAPIPath = "/openapi.json"
+	// maxBodySize bounds the request bodies, which hold a single article.
+	maxBodySize = 1 << 20
+)
+
+// httpStatuses maps gRPC codes to the HTTP statuses the gateway answers with,
+// as grpc-gateway does.
+var httpStatuses = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// forwardedHeaders are the HTTP headers passed on as gRPC metadata.
+var forwardedHeaders = []string{interceptors.RequestIDMetadataKey, principalMetadataKey}
+
+// gatewayRoute is an HTTP method of /entity and the RPC it is mapped to.
+type gatewayRoute struct {
+	method      string
+	rpc         string
+	summary     string
+	request     proto.Message
+	response    proto.Message
+	queryParams []string
+	body        bool
+}
+
+var gatewayRoutes = []gatewayRoute{{
+	method:      http.MethodGet,
+	rpc:         "GetArticle",
+	summary:     "Get an article by id.",
+	request:     &grpcServer.GetArticleIDRequest{},
+	response:    &grpcServer.GetArticleResponse{},
+	queryParams: []string{"id"},
+}, {
+	method:   http.MethodPost,
+	rpc:      "CreateArticle",
+	summary:  "Create an article.",
+	request:  &grpcServer.CreateArticleRequest{},
+	response: &grpcServer.CreateArticleResponse{},
+	body:     true,
+}, {
+	method:   http.MethodPut,
+	rpc:      "UpdateArticle",
+	summary:  "Update an article.",
+	request:  &grpcServer.UpdateArticleRequest{},
+	response: &emptypb.Empty{},
+	body:     true,
+}, {
+	method:      http.MethodDelete,
+	rpc:         "DeleteArticle",
+	summary:     "Delete an article.",
+	request:     &grpcServer.DeleteArticleIDRequest{},
+	response:    &emptypb.Empty{},
+	queryParams: []string{"id", "expected_version"},
+},
+}
+
+// Gateway serves the REST/JSON API of ArticleService by calling it over gRPC,
+// so the calls go through the same interceptors as gRPC clients.
+type Gateway struct {
+	client  grpcServer.ArticleServiceClient
+	openAPI []byte
+}
+
+func NewGateway(client grpcServer.ArticleServiceClient) (*Gateway, error) {
+	openAPI, err := openAPIDocument(gatewayRoutes)
+	if err != nil {
+		return nil, err
+	}
+	return &Gateway{client: client, openAPI: openAPI}, nil
+}
+
+func (gateway *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case openAPIPath:
+		if r.Method != http.MethodGet {
+			writeHTTPError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "method not allowed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(gateway.openAPI)
+		return
+	case entityPath:
+	default:
+		writeHTTPError(w, http.StatusNotFound, codes.NotFound, "not found")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	ctx := outgoingContext(r)
+	var response proto.Message
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		request := &grpcServer.GetArticleIDRequest{}
+		if request.Id, err = queryInt(r, "id", true); err == nil {
+			response, err = gateway.client.GetArticle(ctx, request)
+		}
+	case http.MethodPost:
+		request := &grpcServer.CreateArticleRequest{}
+		if err = readBody(r, request); err == nil {
+			response, err = gateway.client.CreateArticle(ctx, request)
+		}
+	case http.MethodPut:
+		request := &grpcServer.UpdateArticleRequest{}
+		if err = readBody(r, request); err == nil {
+			_, err = gateway.client.UpdateArticle(ctx, request)
+			response = &emptypb.Empty{}
+		}
+	case http.MethodDelete:
+		request := &grpcServer.DeleteArticleIDRequest{}
+		if request.Id, err = queryInt(r, "id", true); err == nil {
+			if request.ExpectedVersion, err = queryInt(r, "expected_version", false); err == nil {
+				_, err = gateway.client.DeleteArticle(ctx, request)
+				response = &emptypb.Empty{}
+			}
+		}
+	default:
+		writeHTTPError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "method not allowed")
+		return
+	}
+	if err != nil {
+		code := status.Code(err)
+		writeHTTPError(w, httpStatuses[code], code, status.Convert(err).Message())
+		return
+	}
+
+	body, err := protojson.Marshal(response)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, codes.Internal, errCreateJson+err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// outgoingContext passes the forwarded headers of r on to the gRPC call.
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, header := range forwardedHeaders {
+		if value := r.Header.Get(header); value != "" {
+			md.Set(header, value)
+		}
+	}
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+func queryInt(r *http.Request, key string, required bool) (int64, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		if required {
+			return 0, status.Errorf(codes.InvalidArgument, "%s %q", errQueryParamKey, key)
+		}
+		return 0, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "%s %v", errParseInt, err)
+	}
+	return parsed, nil
+}
+
+func readBody(r *http.Request, request proto.Message) error {
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return status.Errorf(codes.InvalidArgument, "%s exceeds %d bytes", errReadReqBody, tooLarge.Limit)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s %v", errReadReqBody, err)
+	}
+	if err = protojson.Unmarshal(body, request); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s %v", errParseJson, err)
+	}
+	return nil
+}
+
+// httpError is the body of error responses.
+type httpError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeHTTPError(w http.ResponseWriter, httpStatus int, code codes.Code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(httpError{Code: code.String(), Message: message})
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// fakeArticleClient answers the RPCs the gateway maps and records the last
+// request and its metadata.
+type fakeArticleClient struct {
+	grpcServer.ArticleServiceClient
+	request  any
+	metadata metadata.MD
+}
+
+func (client *fakeArticleClient) record(ctx context.Context, request any) {
+	client.request = request
+	client.metadata, _ = metadata.FromOutgoingContext(ctx)
+}
+
+func (client *fakeArticleClient) GetArticle(ctx context.Context, in *grpcServer.GetArticleIDRequest, _ ...grpc.CallOption) (*grpcServer.GetArticleResponse, error) {
+	client.record(ctx, in)
+	if in.Id != 1 {
+		return nil, status.Error(codes.NotFound, "article not found")
+	}
+	return &grpcServer.GetArticleResponse{Id: 1, Name: "article", Rating: 5, Version: 2}, nil
+}
+
+func (client *fakeArticleClient) CreateArticle(ctx context.Context, in *grpcServer.CreateArticleRequest, _ ...grpc.CallOption) (*grpcServer.CreateArticleResponse, error) {
+	client.record(ctx, in)
+	return &grpcServer.CreateArticleResponse{Id: 1, Name: in.Name, Rating: in.Rating}, nil
+}
+
+func (client *fakeArticleClient) UpdateArticle(ctx context.Context, in *grpcServer.UpdateArticleRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	client.record(ctx, in)
+	return &emptypb.Empty{}, nil
+}
+
+func (client *fakeArticleClient) DeleteArticle(ctx context.Context, in *grpcServer.DeleteArticleIDRequest, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+	client.record(ctx, in)
+	if in.ExpectedVersion != 0 && in.ExpectedVersion != 2 {
+		return nil, status.Error(codes.Aborted, "version mismatch")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func TestGateway(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			target:     "/entity?id=1",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"1","name":"article","rating":"5","version":"2"}`,
+		},
+		{
+			name:       "get without id",
+			method:     http.MethodGet,
+			target:     "/entity",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"InvalidArgument","message":"Failed to find parameter in request \"id\""}`,
+		},
+		{
+			name:       "get missing article",
+			method:     http.MethodGet,
+			target:     "/entity?id=2",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"code":"NotFound","message":"article not found"}`,
+		},
+		{
+			name:       "create",
+			method:     http.MethodPost,
+			target:     "/entity",
+			body:       `{"name":"article","rating":5}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"1","name":"article","rating":"5"}`,
+		},
+		{
+			name:       "create with invalid body",
+			method:     http.MethodPost,
+			target:     "/entity",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "create with oversized body",
+			method:     http.MethodPost,
+			target:     "/entity",
+			body:       `{"name":"` + strings.Repeat("a", maxBodySize) + `"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "update",
+			method:     http.MethodPut,
+			target:     "/entity",
+			body:       `{"id":1,"name":"renamed"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{}`,
+		},
+		{
+			name:       "delete with stale version",
+			method:     http.MethodDelete,
+			target:     "/entity?id=1&expected_version=1",
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"code":"Aborted","message":"version mismatch"}`,
+		},
+		{
+			name:       "unknown path",
+			method:     http.MethodGet,
+			target:     "/entities",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unsupported method",
+			method:     http.MethodPatch,
+			target:     "/entity",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			gateway, err := NewGateway(&fakeArticleClient{})
+			require.NoError(t, err)
+			request := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+
+			// act
+			gateway.ServeHTTP(recorder, request)
+
+			// assert
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestGateway_ForwardsHeaders(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	client := &fakeArticleClient{}
+	gateway, err := NewGateway(client)
+	require.NoError(t, err)
+	request := httptest.NewRequest(http.MethodDelete, "/entity?id=1", nil)
+	request.Header.Set("X-Request-Id", "request")
+	request.Header.Set("X-Principal", "alice")
+
+	// act
+	gateway.ServeHTTP(httptest.NewRecorder(), request)
+
+	// assert
+	assert.Equal(t, &grpcServer.DeleteArticleIDRequest{Id: 1}, client.request)
+	assert.Equal(t, []string{"request"}, client.metadata.Get("x-request-id"))
+	assert.Equal(t, []string{"alice"}, client.metadata.Get("x-principal"))
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	gateway, err := NewGateway(&fakeArticleClient{})
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+
+	// act
+	gateway.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	// assert
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var document struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+	operations := make(map[string]string)
+	for method, operation := range document.Paths["/entity"] {
+		operations[method] = operation.OperationID
+	}
+	assert.Equal(t, map[string]string{
+		"get":    "GetArticle",
+		"post":   "CreateArticle",
+		"put":    "UpdateArticle",
+		"delete": "DeleteArticle",
+	}, operations)
+	assert.Equal(t, map[string]any{"type": "string", "format": "int64"},
+		document.Components.Schemas["GetArticleResponse"].Properties["rating"])
+	assert.Contains(t, document.Components.Schemas, "UpdateArticleRequest")
+}
@@ -6,19 +6,20 @@ import (
 	"fmt"
 	"github.com/NRKA/gRPC-Server/internal/kafka"
 	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/internal/watcher"
+	"github.com/NRKA/gRPC-Server/pkg/events"
 	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
 	"github.com/NRKA/gRPC-Server/pkg/logger"
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/log"
 	"github.com/stretchr/testify/require"
-	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,22 +29,38 @@ const topic = "TOPIC"
 type articleInterface interface {
 	Create(ctx context.Context, article repository.Article) (int64, error)
 	GetByID(ctx context.Context, id int64) (repository.Article, error)
-	Delete(ctx context.Context, id int64) error
-	Update(ctx context.Context, article repository.Article) error
+	Delete(ctx context.Context, id int64, expectedVersion int64) error
+	Update(ctx context.Context, update repository.ArticleUpdate) error
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, id int64) error
+	List(ctx context.Context, params repository.ListParams) ([]repository.Article, error)
+	Search(ctx context.Context, params repository.SearchParams) ([]repository.SearchResult, error)
+	History(ctx context.Context, params repository.HistoryParams) ([]repository.ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64, version int64) (repository.ArticleRevision, error)
+	BatchCreate(ctx context.Context, articles []repository.Article, mode repository.BatchMode) ([]repository.BatchItemResult, error)
+	BatchUpdate(ctx context.Context, updates []repository.ArticleUpdate, mode repository.BatchMode) ([]repository.BatchItemResult, error)
+	BatchDelete(ctx context.Context, ids []int64, mode repository.BatchMode) ([]repository.BatchItemResult, error)
+	Import(ctx context.Context, articles []repository.Article) (repository.ImportResult, error)
 }
 
 type GrpcArticleHandler struct {
-	repo        articleInterface
-	producer    kafka.KafkaInterface
-	currentTime func() time.Time
+	repo            articleInterface
+	producer        kafka.KafkaInterface
+	currentTime     func() time.Time
+	pageTokenSecret []byte
+	watchers        *watcher.Hub
+	importChunkSize int
 	grpcServer.UnimplementedArticleServiceServer
 }
 
 func NewGrpcArticleHandler(repo articleInterface, producer kafka.KafkaInterface) *GrpcArticleHandler {
 	return &GrpcArticleHandler{
-		repo:        repo,
-		producer:    producer,
-		currentTime: time.Now,
+		repo:            repo,
+		producer:        producer,
+		currentTime:     time.Now,
+		pageTokenSecret: defaultPageTokenSecret,
+		watchers:        watcher.NewHub(watcher.DefaultHistorySize),
+		importChunkSize: defaultImportChunkSize,
 	}
 }
 
@@ -51,6 +68,20 @@ func (handler *GrpcArticleHandler) SetCustomTimeFunc(timeFunc func() time.Time)
 	handler.currentTime = timeFunc
 }
 
+// SetPageTokenSecret sets the key used to sign page tokens. All replicas
+// serving the same clients must share it, otherwise tokens issued by one
+// replica are rejected by the others.
+func (handler *GrpcArticleHandler) SetPageTokenSecret(secret []byte) {
+	handler.pageTokenSecret = secret
+}
+
+// SetWatchers replaces the hub WatchArticles subscribes to. The hub is fed
+// with the changes read from the article topic, so it has to be the one the
+// topic is consumed into.
+func (handler *GrpcArticleHandler) SetWatchers(hub *watcher.Hub) {
+	handler.watchers = hub
+}
+
 func DataConvertationСreate(article *grpcServer.CreateArticleRequest) repository.Article {
 	return repository.Article{
 		Name:   article.Name,
@@ -58,46 +89,64 @@ func DataConvertationСreate(article *grpcServer.CreateArticleRequest) repositor
 	}
 }
 
-func DataConvertationUpdate(article *grpcServer.UpdateArticleRequest) repository.Article {
-	return repository.Article{
-		ID:     article.Id,
-		Name:   article.Name,
-		Rating: article.Rating,
-	}
+var updateFields = map[string]repository.ArticleField{
+	"name":   repository.FieldName,
+	"rating": repository.FieldRating,
 }
 
-func (handler *GrpcArticleHandler) CreateArticle(ctx context.Context, article *grpcServer.CreateArticleRequest) (*grpcServer.CreateArticleResponse, error) {
-	l := logger.FromContext(ctx)
-	ctx = logger.ToContext(ctx, l.With(zap.String("method", "CreateArticle")))
+// DataConvertationUpdate converts an update request and validates the fields
+// it writes. Without an update mask every field is written.
+func DataConvertationUpdate(article *grpcServer.UpdateArticleRequest) (repository.ArticleUpdate, error) {
+	update := repository.ArticleUpdate{
+		Article: repository.Article{
+			ID:      article.Id,
+			Name:    article.Name,
+			Rating:  article.Rating,
+			Version: article.ExpectedVersion,
+		},
+		Fields: []repository.ArticleField{repository.FieldName, repository.FieldRating},
+	}
+	if paths := article.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		update.Fields = make([]repository.ArticleField, 0, len(paths))
+		for _, path := range paths {
+			field, ok := updateFields[path]
+			if !ok {
+				return update, fmt.Errorf("%s unknown field %q", errInvalidUpdateMask, path)
+			}
+			update.Fields = append(update.Fields, field)
+		}
+	}
 
-	span, ctx := opentracing.StartSpanFromContext(ctx, "GrpcArticleHandler: CreateArticle")
-	defer span.Finish()
+	for _, field := range update.Fields {
+		if (field == repository.FieldName && article.Name == "") || (field == repository.FieldRating && article.Rating < 1) {
+			return update, errors.New(errInvalidData)
+		}
+	}
+	return update, nil
+}
+
+func DataConvertationArticle(article repository.Article) *grpcServer.Article {
+	return &grpcServer.Article{
+		Id:        article.ID,
+		Name:      article.Name,
+		Rating:    article.Rating,
+		CreatedAt: timestamppb.New(article.CreatedAt),
+		Version:   article.Version,
+		DeletedAt: optionalTimestamp(article.DeletedAt),
+	}
+}
 
+func (handler *GrpcArticleHandler) CreateArticle(ctx context.Context, article *grpcServer.CreateArticleRequest) (*grpcServer.CreateArticleResponse, error) {
 	articleData := DataConvertationСreate(article)
 	if articleData.Name == "" || articleData.Rating < 1 {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(fmt.Errorf(errInvalidData)))
 		return &grpcServer.CreateArticleResponse{}, status.Error(codes.InvalidArgument, errInvalidData)
 	}
 	id, err := handler.repo.Create(ctx, articleData)
 	if err != nil {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
 		return &grpcServer.CreateArticleResponse{}, status.Error(codes.Internal, errArticleCreate+err.Error())
 	}
 	articleData.ID = id
 
-	method, _ := grpc.Method(ctx)
-	err = handler.producer.SendEvent(os.Getenv(topic), kafka.Event{
-		TimeStamp:   handler.currentTime(),
-		Type:        method,
-		RequestBody: article.String(),
-	})
-	if err != nil {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
-	}
-
 	return &grpcServer.CreateArticleResponse{
 		Id:     articleData.ID,
 		Name:   articleData.Name,
@@ -106,112 +155,219 @@ func (handler *GrpcArticleHandler) CreateArticle(ctx context.Context, article *g
 }
 
 func (handler *GrpcArticleHandler) GetArticle(ctx context.Context, id *grpcServer.GetArticleIDRequest) (*grpcServer.GetArticleResponse, error) {
-	l := logger.FromContext(ctx)
-	ctx = logger.ToContext(ctx, l.With(zap.String("method", "GetArticleByID")))
-
-	span, ctx := opentracing.StartSpanFromContext(ctx, "GrpcArticleHandler: GetArticleByID")
-	defer span.Finish()
-
 	article, err := handler.repo.GetByID(ctx, id.Id)
 	if err != nil {
-		if errors.Is(err, repository.ErrArticalNotFound) {
-			span.SetTag("error", true)
-			span.LogFields(log.Error(err))
+		if errors.Is(err, repository.ErrArticalNotFound) || errors.Is(err, repository.ErrArticleDeleted) {
 			return &grpcServer.GetArticleResponse{}, status.Error(codes.NotFound, err.Error())
 		}
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
 		return &grpcServer.GetArticleResponse{}, status.Error(codes.Internal, errArticleGetById+err.Error())
 	}
-	method, _ := grpc.Method(ctx)
 	err = handler.producer.SendEvent(os.Getenv(topic), kafka.Event{
-		TimeStamp:   handler.currentTime(),
-		Type:        method,
-		RequestBody: "",
+		Type:        events.EventType_EVENT_TYPE_ARTICLE_VIEWED,
+		AggregateID: article.ID,
+		After:       &article,
+		Time:        handler.currentTime(),
 	})
 	if err != nil {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
+		logger.Errorf(ctx, "%s: %v", errSendEvent, err)
 	}
 
 	return &grpcServer.GetArticleResponse{
-		Id:     article.ID,
-		Name:   article.Name,
-		Rating: article.Rating,
+		Id:      article.ID,
+		Name:    article.Name,
+		Rating:  article.Rating,
+		Version: article.Version,
 	}, nil
 }
 
 func (handler *GrpcArticleHandler) DeleteArticle(ctx context.Context, id *grpcServer.DeleteArticleIDRequest) (*emptypb.Empty, error) {
-	l := logger.FromContext(ctx)
-	ctx = logger.ToContext(ctx, l.With(zap.String("method", "DeleteArticle")))
-
-	span, ctx := opentracing.StartSpanFromContext(ctx, "GrpcArticleHandler: DeleteArticle")
-	defer span.Finish()
-
-	err := handler.repo.Delete(ctx, id.Id)
+	err := handler.repo.Delete(ctx, id.Id, id.ExpectedVersion)
 	if err != nil {
 		if errors.Is(err, repository.ErrArticalNotFound) {
-			span.SetTag("error", true)
-			span.LogFields(log.Error(err))
 			return nil, status.Error(codes.NotFound, err.Error())
 		}
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
+		if errors.Is(err, repository.ErrArticleDeleted) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
 		return nil, status.Error(codes.Internal, errArticleDelete+err.Error())
 	}
 
-	method, _ := grpc.Method(ctx)
-	err = handler.producer.SendEvent(os.Getenv(topic), kafka.Event{
-		TimeStamp:   handler.currentTime(),
-		Type:        method,
-		RequestBody: "",
-	})
-	if err != nil {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
-	}
-
 	return new(emptypb.Empty), nil
 }
 
 func (handler *GrpcArticleHandler) UpdateArticle(ctx context.Context, article *grpcServer.UpdateArticleRequest) (*emptypb.Empty, error) {
-	l := logger.FromContext(ctx)
-	ctx = logger.ToContext(ctx, l.With(zap.String("method", "UpdateArticle")))
-
-	span, ctx := opentracing.StartSpanFromContext(ctx, "GrpcArticleHandler: UpdateArticle")
-	defer span.Finish()
-
-	articleData := DataConvertationUpdate(article)
-	if article.Name == "" || article.Rating < 1 {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(fmt.Errorf(errInvalidData)))
-		return nil, status.Error(codes.InvalidArgument, errInvalidData)
+	update, err := DataConvertationUpdate(article)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	err := handler.repo.Update(ctx, articleData)
+	err = handler.repo.Update(ctx, update)
 	if err != nil {
 		if errors.Is(err, repository.ErrArticalNotFound) {
-			span.SetTag("error", true)
-			span.LogFields(log.Error(err))
 			return nil, status.Error(codes.NotFound, err.Error())
 		}
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
+		if errors.Is(err, repository.ErrArticleDeleted) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
 		return nil, status.Error(codes.Internal, errArticleUpdate+err.Error())
 	}
 
-	method, _ := grpc.Method(ctx)
-	err = handler.producer.SendEvent(os.Getenv(topic), kafka.Event{
-		TimeStamp:   handler.currentTime(),
-		Type:        method,
-		RequestBody: article.String(),
+	return new(emptypb.Empty), nil
+}
+
+func (handler *GrpcArticleHandler) ListArticles(ctx context.Context, request *grpcServer.ListArticlesRequest) (*grpcServer.ListArticlesResponse, error) {
+	pageSize, err := normalizePageSize(request.PageSize)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidPageSize+err.Error())
+	}
+	filter, err := DataConvertationFilter(request.Filter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidFilter+err.Error())
+	}
+	sort, err := DataConvertationSort(request.SortBy, request.SortDirection)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidSort+err.Error())
+	}
+	query, err := queryDigest(&grpcServer.ListArticlesRequest{
+		Filter:        request.Filter,
+		SortBy:        request.SortBy,
+		SortDirection: request.SortDirection,
 	})
 	if err != nil {
-		span.SetTag("error", true)
-		span.LogFields(log.Error(err))
+		return nil, status.Error(codes.Internal, errArticleList+err.Error())
 	}
 
-	return new(emptypb.Empty), nil
+	params := repository.ListParams{
+		Filter: filter,
+		Sort:   sort,
+		// One extra row tells us whether another page exists without a COUNT query.
+		Limit: pageSize + 1,
+	}
+	if request.PageToken != "" {
+		var token pageToken
+		err := decodePageToken(handler.pageTokenSecret, request.PageToken, &token)
+		if err == nil && token.Query != query {
+			err = errPageTokenQuery
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errInvalidPage+err.Error())
+		}
+		params.After = &token.Cursor
+	}
+
+	articles, err := handler.repo.List(ctx, params)
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleList+err.Error())
+	}
+
+	response := &grpcServer.ListArticlesResponse{}
+	if len(articles) > pageSize {
+		articles = articles[:pageSize]
+		response.NextPageToken, err = encodePageToken(handler.pageTokenSecret, pageToken{
+			Query:  query,
+			Cursor: *repository.CursorOf(articles[len(articles)-1]),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, errArticleList+err.Error())
+		}
+	}
+	response.Articles = make([]*grpcServer.Article, 0, len(articles))
+	for _, article := range articles {
+		response.Articles = append(response.Articles, DataConvertationArticle(article))
+	}
+
+	return response, nil
+}
+
+func (handler *GrpcArticleHandler) SearchArticles(ctx context.Context, request *grpcServer.SearchArticlesRequest) (*grpcServer.SearchArticlesResponse, error) {
+	if strings.TrimSpace(request.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, errEmptyQuery)
+	}
+	pageSize, err := normalizePageSize(request.PageSize)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidPageSize+err.Error())
+	}
+	query, err := queryDigest(&grpcServer.SearchArticlesRequest{Query: request.Query})
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleSearch+err.Error())
+	}
+
+	params := repository.SearchParams{
+		Query: request.Query,
+		Limit: pageSize + 1,
+	}
+	if request.PageToken != "" {
+		var token searchPageToken
+		err := decodePageToken(handler.pageTokenSecret, request.PageToken, &token)
+		if err == nil && token.Query != query {
+			err = errPageTokenQuery
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errInvalidPage+err.Error())
+		}
+		params.After = &token.Cursor
+	}
+
+	results, err := handler.repo.Search(ctx, params)
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleSearch+err.Error())
+	}
+
+	response := &grpcServer.SearchArticlesResponse{}
+	if len(results) > pageSize {
+		results = results[:pageSize]
+		response.NextPageToken, err = encodePageToken(handler.pageTokenSecret, searchPageToken{
+			Query:  query,
+			Cursor: *repository.SearchCursorOf(results[len(results)-1]),
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, errArticleSearch+err.Error())
+		}
+	}
+	response.Results = make([]*grpcServer.ArticleSearchResult, 0, len(results))
+	for _, result := range results {
+		response.Results = append(response.Results, &grpcServer.ArticleSearchResult{
+			Article: DataConvertationArticle(result.Article),
+			Rank:    result.Rank,
+			Snippet: result.Snippet,
+		})
+	}
+
+	return response, nil
+}
+
+func (handler *GrpcArticleHandler) WatchArticles(request *grpcServer.WatchArticlesRequest, stream grpcServer.ArticleService_WatchArticlesServer) error {
+	ctx := stream.Context()
+	filter, err := DataConvertationWatchFilter(request)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, errInvalidFilter+err.Error())
+	}
+	subscription, err := handler.watchers.Subscribe(filter, request.ResumeAfterSequence)
+	if err != nil {
+		return status.Error(codes.OutOfRange, errWatchResume+err.Error())
+	}
+	defer subscription.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case change, ok := <-subscription.Changes():
+			if !ok {
+				err = subscription.Err()
+				return status.Error(codes.Aborted, errWatchDropped+err.Error())
+			}
+			if err = stream.Send(DataConvertationChange(change)); err != nil {
+				return err
+			}
+		}
+	}
 }
 
 func setupGRPCConnection(t *testing.T, server *grpc.Server) (*grpc.ClientConn, func()) {
@@ -7,12 +7,17 @@ import (
 	mock_kafka_interface "github.com/NRKA/gRPC-Server/internal/kafka/mocks"
 	"github.com/NRKA/gRPC-Server/internal/repository"
 	mock_repository "github.com/NRKA/gRPC-Server/internal/repository/mocks"
+	"github.com/NRKA/gRPC-Server/internal/watcher"
+	"github.com/NRKA/gRPC-Server/pkg/events"
 	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"os"
 	"testing"
 	"time"
@@ -23,35 +28,26 @@ func TestArticleHandler_Create(t *testing.T) {
 
 	testCases := []struct {
 		name             string
-		request          grpcServer.CreateArticleRequest
+		request          *grpcServer.CreateArticleRequest
 		mockReturnValue  int64
 		mockError        error
 		expectedCode     codes.Code
 		expectedResponse *grpcServer.CreateArticleResponse
-		mockKafka        func(*gomock.Controller, kafka.Event) kafka.KafkaInterface
 	}{{
 		name:             "success",
-		request:          grpcServer.CreateArticleRequest{Name: "name", Rating: 10},
+		request:          &grpcServer.CreateArticleRequest{Name: "name", Rating: 10},
 		mockReturnValue:  1,
 		mockError:        nil,
 		expectedCode:     codes.OK,
 		expectedResponse: &grpcServer.CreateArticleResponse{Id: 1, Name: "name", Rating: 10},
-		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-			mockProducer := mock_kafka_interface.NewMockKafkaInterface(controller)
-			mockProducer.EXPECT().SendEvent(os.Getenv(topic), event).Return(nil)
-			return mockProducer
-		},
 	},
 		{
 			name:             "internal server error",
-			request:          grpcServer.CreateArticleRequest{Name: "name", Rating: 10},
+			request:          &grpcServer.CreateArticleRequest{Name: "name", Rating: 10},
 			mockReturnValue:  1,
 			mockError:        fmt.Errorf("failed to create article: internal server error"),
 			expectedCode:     codes.Internal,
 			expectedResponse: &grpcServer.CreateArticleResponse{},
-			mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-				return mock_kafka_interface.NewMockKafkaInterface(controller)
-			},
 		},
 	}
 
@@ -63,11 +59,7 @@ func TestArticleHandler_Create(t *testing.T) {
 			// arrange
 			ctrl := gomock.NewController(t)
 			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
-			mockKafka := tc.mockKafka(ctrl, kafka.Event{
-				TimeStamp:   time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local),
-				Type:        "/ArticleService/CreateArticle",
-				RequestBody: tc.request.String(),
-			})
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
 
 			server := grpc.NewServer()
 			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
@@ -78,15 +70,11 @@ func TestArticleHandler_Create(t *testing.T) {
 			}).Return(tc.mockReturnValue, tc.mockError)
 			defer ctrl.Finish()
 
-			handler.SetCustomTimeFunc(func() time.Time {
-				return time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local)
-			})
-
 			conn, closeConnAndServer := setupGRPCConnection(t, server)
 			defer closeConnAndServer()
 
 			client := grpcServer.NewArticleServiceClient(conn)
-			response, err := client.CreateArticle(context.Background(), &tc.request)
+			response, err := client.CreateArticle(context.Background(), tc.request)
 
 			if err != nil {
 				st, _ := status.FromError(err)
@@ -105,7 +93,7 @@ func TestArticleHandler_GetByID(t *testing.T) {
 
 	testCases := []struct {
 		name              string
-		request           grpcServer.GetArticleIDRequest
+		request           *grpcServer.GetArticleIDRequest
 		mockReturnArticle repository.Article
 		mockError         error
 		expectedCode      codes.Code
@@ -113,22 +101,31 @@ func TestArticleHandler_GetByID(t *testing.T) {
 		mockKafka         func(*gomock.Controller, kafka.Event) kafka.KafkaInterface
 	}{{
 		name:              "success",
-		request:           grpcServer.GetArticleIDRequest{Id: 1},
-		mockReturnArticle: repository.Article{ID: 1, Name: "name", Rating: 10},
+		request:           &grpcServer.GetArticleIDRequest{Id: 1},
+		mockReturnArticle: repository.Article{ID: 1, Name: "name", Rating: 10, Version: 3},
 		mockError:         nil,
 		expectedCode:      codes.OK,
-		expectedResponse:  &grpcServer.GetArticleResponse{Id: 1, Name: "name", Rating: 10},
+		expectedResponse:  &grpcServer.GetArticleResponse{Id: 1, Name: "name", Rating: 10, Version: 3},
 		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
 			mockProducer := mock_kafka_interface.NewMockKafkaInterface(controller)
 			mockProducer.EXPECT().SendEvent(os.Getenv(topic), event).Return(nil)
 			return mockProducer
 		}}, {
 		name:              "article not found",
-		request:           grpcServer.GetArticleIDRequest{Id: 99},
+		request:           &grpcServer.GetArticleIDRequest{Id: 99},
 		mockReturnArticle: repository.Article{},
 		mockError:         repository.ErrArticalNotFound,
 		expectedCode:      codes.NotFound,
 		expectedResponse:  &grpcServer.GetArticleResponse{},
+		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
+			return mock_kafka_interface.NewMockKafkaInterface(controller)
+		}}, {
+		name:              "article deleted",
+		request:           &grpcServer.GetArticleIDRequest{Id: 98},
+		mockReturnArticle: repository.Article{},
+		mockError:         repository.ErrArticleDeleted,
+		expectedCode:      codes.NotFound,
+		expectedResponse:  &grpcServer.GetArticleResponse{},
 		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
 			return mock_kafka_interface.NewMockKafkaInterface(controller)
 		}},
@@ -144,9 +141,10 @@ func TestArticleHandler_GetByID(t *testing.T) {
 			defer ctrl.Finish()
 			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
 			mockKafka := tc.mockKafka(ctrl, kafka.Event{
-				TimeStamp:   time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local),
-				Type:        "/ArticleService/GetArticle",
-				RequestBody: "",
+				Type:        events.EventType_EVENT_TYPE_ARTICLE_VIEWED,
+				AggregateID: tc.mockReturnArticle.ID,
+				After:       &tc.mockReturnArticle,
+				Time:        time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local),
 			})
 
 			server := grpc.NewServer()
@@ -162,7 +160,7 @@ func TestArticleHandler_GetByID(t *testing.T) {
 			defer closeConnAndServer()
 
 			client := grpcServer.NewArticleServiceClient(conn)
-			response, err := client.GetArticle(context.Background(), &tc.request)
+			response, err := client.GetArticle(context.Background(), tc.request)
 
 			if err != nil {
 				st, _ := status.FromError(err)
@@ -172,6 +170,7 @@ func TestArticleHandler_GetByID(t *testing.T) {
 			assert.Equal(t, tc.expectedResponse.Id, response.Id)
 			assert.Equal(t, tc.expectedResponse.Name, response.Name)
 			assert.Equal(t, tc.expectedResponse.Rating, response.Rating)
+			assert.Equal(t, tc.expectedResponse.Version, response.Version)
 		})
 	}
 }
@@ -181,36 +180,34 @@ func TestArticleHandler_Delete(t *testing.T) {
 
 	testCases := []struct {
 		name         string
-		request      grpcServer.DeleteArticleIDRequest
+		request      *grpcServer.DeleteArticleIDRequest
 		mockError    error
 		expectedCode codes.Code
-		mockKafka    func(*gomock.Controller, kafka.Event) kafka.KafkaInterface
 	}{{
 		name:         "success",
-		request:      grpcServer.DeleteArticleIDRequest{Id: 1},
+		request:      &grpcServer.DeleteArticleIDRequest{Id: 1},
 		mockError:    nil,
 		expectedCode: codes.OK,
-		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-			mockProducer := mock_kafka_interface.NewMockKafkaInterface(controller)
-			mockProducer.EXPECT().SendEvent(os.Getenv(topic), event).Return(nil)
-			return mockProducer
-		},
 	}, {
 		name:         "article not found",
-		request:      grpcServer.DeleteArticleIDRequest{Id: 9999},
+		request:      &grpcServer.DeleteArticleIDRequest{Id: 9999},
 		mockError:    repository.ErrArticalNotFound,
 		expectedCode: codes.NotFound,
-		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-			return mock_kafka_interface.NewMockKafkaInterface(controller)
-		},
+	}, {
+		name:         "article already deleted",
+		request:      &grpcServer.DeleteArticleIDRequest{Id: 1},
+		mockError:    repository.ErrArticleDeleted,
+		expectedCode: codes.FailedPrecondition,
+	}, {
+		name:         "version conflict",
+		request:      &grpcServer.DeleteArticleIDRequest{Id: 1, ExpectedVersion: 2},
+		mockError:    repository.ErrVersionConflict,
+		expectedCode: codes.Aborted,
 	}, {
 		name:         "internal server error",
-		request:      grpcServer.DeleteArticleIDRequest{Id: 9999},
+		request:      &grpcServer.DeleteArticleIDRequest{Id: 9999},
 		mockError:    fmt.Errorf("failed to delete article"),
 		expectedCode: codes.Internal,
-		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-			return mock_kafka_interface.NewMockKafkaInterface(controller)
-		},
 	},
 	}
 
@@ -223,25 +220,17 @@ func TestArticleHandler_Delete(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
-			mockKafka := tc.mockKafka(ctrl, kafka.Event{
-				TimeStamp:   time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local),
-				Type:        "/ArticleService/DeleteArticle",
-				RequestBody: "",
-			})
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
 			server := grpc.NewServer()
 			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
 			grpcServer.RegisterArticleServiceServer(server, handler)
-			mockRepo.EXPECT().Delete(gomock.Any(), tc.request.Id).Return(tc.mockError)
-
-			handler.SetCustomTimeFunc(func() time.Time {
-				return time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local)
-			})
+			mockRepo.EXPECT().Delete(gomock.Any(), tc.request.Id, tc.request.ExpectedVersion).Return(tc.mockError)
 
 			conn, closeConnAndServer := setupGRPCConnection(t, server)
 			defer closeConnAndServer()
 
 			client := grpcServer.NewArticleServiceClient(conn)
-			_, err := client.DeleteArticle(context.Background(), &tc.request)
+			_, err := client.DeleteArticle(context.Background(), tc.request)
 
 			if err != nil {
 				st, _ := status.FromError(err)
@@ -254,30 +243,203 @@ func TestArticleHandler_Delete(t *testing.T) {
 func TestArticleHandler_Update(t *testing.T) {
 	t.Parallel()
 
+	allFields := []repository.ArticleField{repository.FieldName, repository.FieldRating}
 	testCases := []struct {
-		name         string
-		request      grpcServer.UpdateArticleRequest
-		mockError    error
-		expectedCode codes.Code
-		mockKafka    func(*gomock.Controller, kafka.Event) kafka.KafkaInterface
+		name           string
+		request        *grpcServer.UpdateArticleRequest
+		mockError      error
+		expectedFields []repository.ArticleField
+		expectedCode   codes.Code
 	}{{
-		name:         "success",
-		request:      grpcServer.UpdateArticleRequest{Id: 123, Name: "name", Rating: 10},
-		mockError:    nil,
-		expectedCode: codes.OK,
-		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-			mockProducer := mock_kafka_interface.NewMockKafkaInterface(controller)
-			mockProducer.EXPECT().SendEvent(os.Getenv(topic), event).Return(nil)
-			return mockProducer
+		name:           "success",
+		request:        &grpcServer.UpdateArticleRequest{Id: 123, Name: "name", Rating: 10},
+		mockError:      nil,
+		expectedFields: allFields,
+		expectedCode:   codes.OK,
+	}, {
+		name:           "article not found",
+		request:        &grpcServer.UpdateArticleRequest{Id: 123123, Name: "name", Rating: 10},
+		mockError:      repository.ErrArticalNotFound,
+		expectedFields: allFields,
+		expectedCode:   codes.NotFound,
+	}, {
+		name:           "version conflict",
+		request:        &grpcServer.UpdateArticleRequest{Id: 123, Name: "name", Rating: 10, ExpectedVersion: 3},
+		mockError:      repository.ErrVersionConflict,
+		expectedFields: allFields,
+		expectedCode:   codes.Aborted,
+	}, {
+		name: "partial update skips unmasked fields",
+		request: &grpcServer.UpdateArticleRequest{Id: 123, Rating: 10,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rating"}}},
+		expectedFields: []repository.ArticleField{repository.FieldRating},
+		expectedCode:   codes.OK,
+	}, {
+		name: "masked field is validated",
+		request: &grpcServer.UpdateArticleRequest{Id: 123, Rating: 10,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "rating"}}},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name: "unknown field in mask",
+		request: &grpcServer.UpdateArticleRequest{Id: 123, Name: "name", Rating: 10,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"created_at"}}},
+		expectedCode: codes.InvalidArgument,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			if tc.expectedFields != nil {
+				mockRepo.EXPECT().Update(gomock.Any(), repository.ArticleUpdate{
+					Article: repository.Article{
+						ID:      tc.request.Id,
+						Name:    tc.request.Name,
+						Rating:  tc.request.Rating,
+						Version: tc.request.ExpectedVersion,
+					},
+					Fields: tc.expectedFields,
+				}).Return(tc.mockError)
+			}
+			defer ctrl.Finish()
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+
+			client := grpcServer.NewArticleServiceClient(conn)
+			_, err := client.UpdateArticle(context.Background(), tc.request)
+			if err != nil {
+				st, _ := status.FromError(err)
+				assert.Equal(t, tc.expectedCode, st.Code())
+			}
+		})
+	}
+}
+
+func TestArticleHandler_List(t *testing.T) {
+	t.Parallel()
+
+	query, err := queryDigest(&grpcServer.ListArticlesRequest{})
+	require.NoError(t, err)
+	secondPage, err := encodePageToken(defaultPageTokenSecret, pageToken{
+		Query:  query,
+		Cursor: repository.ListCursor{ID: 2},
+	})
+	require.NoError(t, err)
+	forged, err := encodePageToken([]byte("forged"), pageToken{
+		Query:  query,
+		Cursor: repository.ListCursor{ID: 2},
+	})
+	require.NoError(t, err)
+	minRating, maxRating := int64(5), int64(1)
+	createdAfter := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name             string
+		request          *grpcServer.ListArticlesRequest
+		expectedParams   *repository.ListParams
+		mockReturnValue  []repository.Article
+		mockError        error
+		expectedCode     codes.Code
+		expectedIDs      []int64
+		expectedNextPage bool
+	}{{
+		name:             "first page",
+		request:          &grpcServer.ListArticlesRequest{PageSize: 2},
+		expectedParams:   &repository.ListParams{Limit: 3},
+		mockReturnValue:  []repository.Article{{ID: 1}, {ID: 2}, {ID: 3}},
+		expectedCode:     codes.OK,
+		expectedIDs:      []int64{1, 2},
+		expectedNextPage: true,
+	}, {
+		name:            "last page",
+		request:         &grpcServer.ListArticlesRequest{PageSize: 2, PageToken: secondPage},
+		expectedParams:  &repository.ListParams{After: &repository.ListCursor{ID: 2}, Limit: 3},
+		mockReturnValue: []repository.Article{{ID: 3}},
+		expectedCode:    codes.OK,
+		expectedIDs:     []int64{3},
+	}, {
+		name:            "default page size",
+		request:         &grpcServer.ListArticlesRequest{},
+		expectedParams:  &repository.ListParams{Limit: defaultPageSize + 1},
+		mockReturnValue: []repository.Article{},
+		expectedCode:    codes.OK,
+		expectedIDs:     []int64{},
+	}, {
+		name:            "page size above maximum",
+		request:         &grpcServer.ListArticlesRequest{PageSize: maxPageSize * 10},
+		expectedParams:  &repository.ListParams{Limit: maxPageSize + 1},
+		mockReturnValue: []repository.Article{},
+		expectedCode:    codes.OK,
+		expectedIDs:     []int64{},
+	}, {
+		name: "filter and sort",
+		request: &grpcServer.ListArticlesRequest{
+			PageSize: 2,
+			Filter: &grpcServer.ArticleFilter{
+				MinRating:    &maxRating,
+				NameContains: "go",
+				CreatedAfter: timestamppb.New(createdAfter),
+			},
+			SortBy:        grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_RATING,
+			SortDirection: grpcServer.SortDirection_SORT_DIRECTION_DESC,
 		},
+		expectedParams: &repository.ListParams{
+			Filter: repository.ArticleFilter{
+				MinRating:    &maxRating,
+				NameContains: "go",
+				CreatedAfter: &createdAfter,
+			},
+			Sort:  repository.ArticleSort{Field: repository.SortByRating, Direction: repository.SortDescending},
+			Limit: 3,
+		},
+		mockReturnValue: []repository.Article{{ID: 7}},
+		expectedCode:    codes.OK,
+		expectedIDs:     []int64{7},
 	}, {
-		name:         "article not found",
-		request:      grpcServer.UpdateArticleRequest{Id: 123123, Name: "name", Rating: 10},
-		mockError:    repository.ErrArticalNotFound,
-		expectedCode: codes.NotFound,
-		mockKafka: func(controller *gomock.Controller, event kafka.Event) kafka.KafkaInterface {
-			return mock_kafka_interface.NewMockKafkaInterface(controller)
+		name:         "negative page size",
+		request:      &grpcServer.ListArticlesRequest{PageSize: -1},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:         "malformed page token",
+		request:      &grpcServer.ListArticlesRequest{PageToken: "not-a-token"},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:         "forged page token",
+		request:      &grpcServer.ListArticlesRequest{PageToken: forged},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name: "page token for another sort",
+		request: &grpcServer.ListArticlesRequest{
+			PageToken: secondPage,
+			SortBy:    grpcServer.ArticleSortField_ARTICLE_SORT_FIELD_NAME,
 		},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name: "inverted rating range",
+		request: &grpcServer.ListArticlesRequest{
+			Filter: &grpcServer.ArticleFilter{MinRating: &minRating, MaxRating: &maxRating},
+		},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:         "unknown sort field",
+		request:      &grpcServer.ListArticlesRequest{SortBy: 42},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:           "internal server error",
+		request:        &grpcServer.ListArticlesRequest{PageSize: 2},
+		expectedParams: &repository.ListParams{Limit: 3},
+		mockError:      fmt.Errorf("failed to list articles"),
+		expectedCode:   codes.Internal,
 	},
 	}
 
@@ -288,35 +450,220 @@ func TestArticleHandler_Update(t *testing.T) {
 
 			// arrange
 			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
-			mockKafka := tc.mockKafka(ctrl, kafka.Event{
-				TimeStamp:   time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local),
-				Type:        "/ArticleService/UpdateArticle",
-				RequestBody: tc.request.String(),
-			})
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
 			server := grpc.NewServer()
 			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
 			grpcServer.RegisterArticleServiceServer(server, handler)
-			mockRepo.EXPECT().Update(gomock.Any(), repository.Article{
-				ID:     tc.request.Id,
-				Name:   tc.request.Name,
-				Rating: tc.request.Rating,
-			}).Return(tc.mockError)
+			if tc.expectedParams != nil {
+				mockRepo.EXPECT().List(gomock.Any(), *tc.expectedParams).Return(tc.mockReturnValue, tc.mockError)
+			}
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+
+			client := grpcServer.NewArticleServiceClient(conn)
+			response, err := client.ListArticles(context.Background(), tc.request)
+
+			if tc.expectedCode != codes.OK {
+				st, _ := status.FromError(err)
+				assert.Equal(t, tc.expectedCode, st.Code())
+				return
+			}
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(response.Articles))
+			for _, article := range response.Articles {
+				ids = append(ids, article.Id)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedNextPage, response.NextPageToken != "")
+		})
+	}
+}
+
+func TestArticleHandler_Search(t *testing.T) {
+	t.Parallel()
+
+	query, err := queryDigest(&grpcServer.SearchArticlesRequest{Query: "golang"})
+	require.NoError(t, err)
+	secondPage, err := encodePageToken(defaultPageTokenSecret, searchPageToken{
+		Query:  query,
+		Cursor: repository.SearchCursor{Rank: 0.5, ID: 2},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		request          *grpcServer.SearchArticlesRequest
+		expectedParams   *repository.SearchParams
+		mockReturnValue  []repository.SearchResult
+		mockError        error
+		expectedCode     codes.Code
+		expectedIDs      []int64
+		expectedNextPage bool
+	}{{
+		name:           "first page",
+		request:        &grpcServer.SearchArticlesRequest{Query: "golang", PageSize: 1},
+		expectedParams: &repository.SearchParams{Query: "golang", Limit: 2},
+		mockReturnValue: []repository.SearchResult{
+			{Article: repository.Article{ID: 3}, Rank: 0.9, Snippet: "<mark>golang</mark>"},
+			{Article: repository.Article{ID: 1}, Rank: 0.5},
+		},
+		expectedCode:     codes.OK,
+		expectedIDs:      []int64{3},
+		expectedNextPage: true,
+	}, {
+		name:            "next page",
+		request:         &grpcServer.SearchArticlesRequest{Query: "golang", PageToken: secondPage},
+		expectedParams:  &repository.SearchParams{Query: "golang", After: &repository.SearchCursor{Rank: 0.5, ID: 2}, Limit: defaultPageSize + 1},
+		mockReturnValue: []repository.SearchResult{{Article: repository.Article{ID: 4}, Rank: 0.1}},
+		expectedCode:    codes.OK,
+		expectedIDs:     []int64{4},
+	}, {
+		name:         "empty query",
+		request:      &grpcServer.SearchArticlesRequest{Query: "  "},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:         "page token for another query",
+		request:      &grpcServer.SearchArticlesRequest{Query: "rust", PageToken: secondPage},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:           "internal server error",
+		request:        &grpcServer.SearchArticlesRequest{Query: "golang"},
+		expectedParams: &repository.SearchParams{Query: "golang", Limit: defaultPageSize + 1},
+		mockError:      fmt.Errorf("failed to search articles"),
+		expectedCode:   codes.Internal,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
 
-			handler.SetCustomTimeFunc(func() time.Time {
-				return time.Date(2023, 10, 22, 22, 22, 22, 22, time.Local)
-			})
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			if tc.expectedParams != nil {
+				mockRepo.EXPECT().Search(gomock.Any(), *tc.expectedParams).Return(tc.mockReturnValue, tc.mockError)
+			}
 
 			conn, closeConnAndServer := setupGRPCConnection(t, server)
 			defer closeConnAndServer()
 
 			client := grpcServer.NewArticleServiceClient(conn)
-			_, err := client.UpdateArticle(context.Background(), &tc.request)
-			if err != nil {
+			response, err := client.SearchArticles(context.Background(), tc.request)
+
+			if tc.expectedCode != codes.OK {
 				st, _ := status.FromError(err)
 				assert.Equal(t, tc.expectedCode, st.Code())
+				return
 			}
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(response.Results))
+			for _, result := range response.Results {
+				ids = append(ids, result.Article.Id)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedNextPage, response.NextPageToken != "")
 		})
 	}
 }
+
+func TestArticleHandler_Watch(t *testing.T) {
+	t.Parallel()
+
+	resumeFromStart := uint64(0)
+	resumeFromFuture := uint64(100)
+	testCases := []struct {
+		name             string
+		request          *grpcServer.WatchArticlesRequest
+		expectedCode     codes.Code
+		expectedSequence uint64
+		expectedID       int64
+	}{{
+		name:             "replay from start",
+		request:          &grpcServer.WatchArticlesRequest{ResumeAfterSequence: &resumeFromStart},
+		expectedCode:     codes.OK,
+		expectedSequence: 1,
+		expectedID:       1,
+	}, {
+		name:             "filtered by id",
+		request:          &grpcServer.WatchArticlesRequest{Ids: []int64{2}, ResumeAfterSequence: &resumeFromStart},
+		expectedCode:     codes.OK,
+		expectedSequence: 2,
+		expectedID:       2,
+	}, {
+		name:         "unknown sequence",
+		request:      &grpcServer.WatchArticlesRequest{ResumeAfterSequence: &resumeFromFuture},
+		expectedCode: codes.OutOfRange,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			for _, id := range []int64{1, 2} {
+				handler.watchers.Publish(watcher.Change{Type: watcher.Created, Article: repository.Article{ID: id, Name: "name", Rating: 10}})
+			}
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+
+			client := grpcServer.NewArticleServiceClient(conn)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			stream, err := client.WatchArticles(ctx, tc.request)
+			require.NoError(t, err)
+			change, err := stream.Recv()
+
+			if tc.expectedCode != codes.OK {
+				st, _ := status.FromError(err)
+				assert.Equal(t, tc.expectedCode, st.Code())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSequence, change.Sequence)
+			assert.Equal(t, grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_CREATED, change.Type)
+			assert.Equal(t, tc.expectedID, change.Article.Id)
+		})
+	}
+}
+
+func TestDataConvertationChange_Deleted(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	deletedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	change := watcher.Change{Sequence: 3, Type: watcher.Deleted,
+		Article: repository.Article{ID: 1, Name: "name", Rating: 7, Version: 2, DeletedAt: &deletedAt}}
+
+	// act
+	converted := DataConvertationChange(change)
+
+	// assert
+	assert.Equal(t, grpcServer.ArticleChangeType_ARTICLE_CHANGE_TYPE_DELETED, converted.Type)
+	assert.Equal(t, "name", converted.Article.Name)
+	assert.Equal(t, int64(7), converted.Article.Rating)
+	assert.Equal(t, deletedAt, converted.Article.DeletedAt.AsTime())
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func DataConvertationRevision(revision repository.ArticleRevision) *grpcServer.ArticleRevision {
+	return &grpcServer.ArticleRevision{
+		ArticleId:     revision.ArticleID,
+		Version:       revision.Version,
+		Name:          revision.Name,
+		Rating:        revision.Rating,
+		Deleted:       revision.Deleted,
+		ChangedFields: revision.ChangedFields,
+		Principal:     revision.Principal,
+		Time:          timestamppb.New(revision.CreatedAt),
+	}
+}
+
+func (handler *GrpcArticleHandler) GetArticleHistory(ctx context.Context, request *grpcServer.GetArticleHistoryRequest) (*grpcServer.GetArticleHistoryResponse, error) {
+	pageSize, err := normalizePageSize(request.PageSize)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errInvalidPageSize+err.Error())
+	}
+	query, err := queryDigest(&grpcServer.GetArticleHistoryRequest{Id: request.Id})
+	if err != nil {
+		return nil, status.Error(codes.Internal, errArticleHistory+err.Error())
+	}
+
+	params := repository.HistoryParams{
+		ArticleID: request.Id,
+		Limit:     pageSize + 1,
+	}
+	if request.PageToken != "" {
+		var token historyPageToken
+		err := decodePageToken(handler.pageTokenSecret, request.PageToken, &token)
+		if err == nil && token.Query != query {
+			err = errPageTokenQuery
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errInvalidPage+err.Error())
+		}
+		params.BeforeVersion = token.Version
+	}
+
+	revisions, err := handler.repo.History(ctx, params)
+	if err != nil {
+		if errors.Is(err, repository.ErrArticalNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, errArticleHistory+err.Error())
+	}
+
+	response := &grpcServer.GetArticleHistoryResponse{}
+	if len(revisions) > pageSize {
+		revisions = revisions[:pageSize]
+		response.NextPageToken, err = encodePageToken(handler.pageTokenSecret, historyPageToken{
+			Query:   query,
+			Version: revisions[len(revisions)-1].Version,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Internal, errArticleHistory+err.Error())
+		}
+	}
+	response.Revisions = make([]*grpcServer.ArticleRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, DataConvertationRevision(revision))
+	}
+
+	return response, nil
+}
+
+func (handler *GrpcArticleHandler) GetArticleAtVersion(ctx context.Context, request *grpcServer.GetArticleAtVersionRequest) (*grpcServer.ArticleRevision, error) {
+	if request.Version < 1 {
+		err := fmt.Errorf("version must be positive, got %d", request.Version)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	revision, err := handler.repo.GetRevision(ctx, request.Id, request.Version)
+	if err != nil {
+		if errors.Is(err, repository.ErrRevisionNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, errArticleRevision+err.Error())
+	}
+
+	return DataConvertationRevision(revision), nil
+}
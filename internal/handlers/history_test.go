@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mock_kafka_interface "github.com/NRKA/gRPC-Server/internal/kafka/mocks"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	mock_repository "github.com/NRKA/gRPC-Server/internal/repository/mocks"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestArticleHandler_GetArticleHistory(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+	mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+	server := grpc.NewServer()
+	handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+	grpcServer.RegisterArticleServiceServer(server, handler)
+	gomock.InOrder(
+		mockRepo.EXPECT().History(gomock.Any(), repository.HistoryParams{ArticleID: 1, Limit: 3}).Return([]repository.ArticleRevision{
+			{ArticleID: 1, Version: 3, Name: "third", Rating: 3, ChangedFields: []string{"name"}, Principal: "alice"},
+			{ArticleID: 1, Version: 2, Name: "second", Rating: 3, ChangedFields: []string{"rating"}},
+			{ArticleID: 1, Version: 1, Name: "first", Rating: 1, ChangedFields: []string{"name", "rating"}},
+		}, nil),
+		mockRepo.EXPECT().History(gomock.Any(), repository.HistoryParams{ArticleID: 1, BeforeVersion: 2, Limit: 3}).Return([]repository.ArticleRevision{
+			{ArticleID: 1, Version: 1, Name: "first", Rating: 1, ChangedFields: []string{"name", "rating"}},
+		}, nil),
+		mockRepo.EXPECT().History(gomock.Any(), repository.HistoryParams{ArticleID: 3, Limit: 3}).Return(nil, repository.ErrArticalNotFound),
+	)
+
+	conn, closeConnAndServer := setupGRPCConnection(t, server)
+	defer closeConnAndServer()
+	client := grpcServer.NewArticleServiceClient(conn)
+
+	// act
+	first, err := client.GetArticleHistory(context.Background(), &grpcServer.GetArticleHistoryRequest{Id: 1, PageSize: 2})
+	require.NoError(t, err)
+	second, err := client.GetArticleHistory(context.Background(), &grpcServer.GetArticleHistoryRequest{
+		Id: 1, PageSize: 2, PageToken: first.NextPageToken,
+	})
+	require.NoError(t, err)
+	_, otherArticleErr := client.GetArticleHistory(context.Background(), &grpcServer.GetArticleHistoryRequest{
+		Id: 2, PageSize: 2, PageToken: first.NextPageToken,
+	})
+	_, unknownErr := client.GetArticleHistory(context.Background(), &grpcServer.GetArticleHistoryRequest{Id: 3, PageSize: 2})
+
+	// assert
+	require.Len(t, first.Revisions, 2)
+	assert.Equal(t, "alice", first.Revisions[0].Principal)
+	assert.Equal(t, []string{"rating"}, first.Revisions[1].ChangedFields)
+	assert.NotEmpty(t, first.NextPageToken)
+	require.Len(t, second.Revisions, 1)
+	assert.Equal(t, int64(1), second.Revisions[0].Version)
+	assert.Empty(t, second.NextPageToken)
+	assert.Equal(t, codes.InvalidArgument, status.Code(otherArticleErr))
+	assert.Equal(t, codes.NotFound, status.Code(unknownErr))
+}
+
+func TestArticleHandler_GetArticleAtVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		request          *grpcServer.GetArticleAtVersionRequest
+		mockReturnValue  repository.ArticleRevision
+		mockError        error
+		expectedCode     codes.Code
+		expectedRevision *grpcServer.ArticleRevision
+	}{{
+		name:            "success",
+		request:         &grpcServer.GetArticleAtVersionRequest{Id: 1, Version: 2},
+		mockReturnValue: repository.ArticleRevision{ArticleID: 1, Version: 2, Name: "name", Rating: 5, Deleted: true},
+		expectedCode:    codes.OK,
+	}, {
+		name:         "revision not found",
+		request:      &grpcServer.GetArticleAtVersionRequest{Id: 1, Version: 9},
+		mockError:    repository.ErrRevisionNotFound,
+		expectedCode: codes.NotFound,
+	}, {
+		name:         "invalid version",
+		request:      &grpcServer.GetArticleAtVersionRequest{Id: 1},
+		expectedCode: codes.InvalidArgument,
+	}, {
+		name:         "internal server error",
+		request:      &grpcServer.GetArticleAtVersionRequest{Id: 1, Version: 1},
+		mockError:    fmt.Errorf("connection refused"),
+		expectedCode: codes.Internal,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			if tc.expectedCode != codes.InvalidArgument {
+				mockRepo.EXPECT().GetRevision(gomock.Any(), tc.request.Id, tc.request.Version).Return(tc.mockReturnValue, tc.mockError)
+			}
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+			client := grpcServer.NewArticleServiceClient(conn)
+
+			// act
+			revision, err := client.GetArticleAtVersion(context.Background(), tc.request)
+
+			// assert
+			if tc.expectedCode != codes.OK {
+				assert.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.mockReturnValue.Version, revision.Version)
+			assert.Equal(t, tc.mockReturnValue.Name, revision.Name)
+			assert.True(t, revision.Deleted)
+		})
+	}
+}
+
+func TestPrincipalInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+	mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(PrincipalInterceptor))
+	handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+	grpcServer.RegisterArticleServiceServer(server, handler)
+	var principal string
+	mockRepo.EXPECT().Delete(gomock.Any(), int64(1), int64(0)).DoAndReturn(func(ctx context.Context, id int64, expectedVersion int64) error {
+		principal = repository.PrincipalFromContext(ctx)
+		return nil
+	})
+
+	conn, closeConnAndServer := setupGRPCConnection(t, server)
+	defer closeConnAndServer()
+	client := grpcServer.NewArticleServiceClient(conn)
+
+	// act
+	ctx := metadata.AppendToOutgoingContext(context.Background(), principalMetadataKey, "alice")
+	_, err := client.DeleteArticle(ctx, &grpcServer.DeleteArticleIDRequest{Id: 1})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "alice", principal)
+}
+
+func TestStreamPrincipalInterceptor(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+	mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+	server := grpc.NewServer(grpc.StreamInterceptor(StreamPrincipalInterceptor))
+	handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+	grpcServer.RegisterArticleServiceServer(server, handler)
+	var principal string
+	mockRepo.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, articles []repository.Article) (repository.ImportResult, error) {
+		principal = repository.PrincipalFromContext(ctx)
+		return repository.ImportResult{Inserted: int64(len(articles))}, nil
+	})
+
+	conn, closeConnAndServer := setupGRPCConnection(t, server)
+	defer closeConnAndServer()
+	client := grpcServer.NewArticleServiceClient(conn)
+
+	// act
+	ctx := metadata.AppendToOutgoingContext(context.Background(), principalMetadataKey, "alice")
+	stream, err := client.ImportArticles(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&grpcServer.CreateArticleRequest{Name: "imported", Rating: 1}))
+	_, err = stream.CloseAndRecv()
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "alice", principal)
+}
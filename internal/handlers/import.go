@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultImportChunkSize = 1000
+	maxImportErrors        = 100
+)
+
+func (handler *GrpcArticleHandler) ImportArticles(stream grpcServer.ArticleService_ImportArticlesServer) error {
+	ctx := stream.Context()
+	summary := &grpcServer.ImportArticlesResponse{}
+	chunk := make([]repository.Article, 0, handler.importChunkSize)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		result, err := handler.repo.Import(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		summary.Inserted += result.Inserted
+		summary.Duplicates += result.Duplicates
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for index := int64(0); ; index++ {
+		article, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		articleData := DataConvertationСreate(article)
+		if articleData.Name == "" || articleData.Rating < 1 {
+			summary.Rejected++
+			if len(summary.Errors) < maxImportErrors {
+				summary.Errors = append(summary.Errors, &grpcServer.ImportError{Index: index, Message: errInvalidData})
+			}
+			continue
+		}
+		chunk = append(chunk, articleData)
+		if len(chunk) < handler.importChunkSize {
+			continue
+		}
+		if err = flush(); err != nil {
+			return status.Error(codes.Internal, fmt.Sprintf("%s %v (%d articles inserted before the failure)",
+				errArticleImport, err, summary.Inserted))
+		}
+	}
+
+	if err := flush(); err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("%s %v (%d articles inserted before the failure)",
+			errArticleImport, err, summary.Inserted))
+	}
+	return stream.SendAndClose(summary)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mock_kafka_interface "github.com/NRKA/gRPC-Server/internal/kafka/mocks"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	mock_repository "github.com/NRKA/gRPC-Server/internal/repository/mocks"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestArticleHandler_Import(t *testing.T) {
+	t.Parallel()
+
+	articles := []*grpcServer.CreateArticleRequest{
+		{Name: "a", Rating: 1},
+		{Name: "", Rating: 1},
+		{Name: "c", Rating: 1},
+		{Name: "d", Rating: 1},
+		{Name: "e", Rating: 0},
+		{Name: "f", Rating: 1},
+	}
+	testCases := []struct {
+		name             string
+		mockChunks       [][]repository.Article
+		mockReturnValue  []repository.ImportResult
+		mockError        error
+		expectedCode     codes.Code
+		expectedResponse *grpcServer.ImportArticlesResponse
+	}{{
+		name: "success",
+		mockChunks: [][]repository.Article{
+			{{Name: "a", Rating: 1}, {Name: "c", Rating: 1}},
+			{{Name: "d", Rating: 1}, {Name: "f", Rating: 1}},
+		},
+		mockReturnValue: []repository.ImportResult{{Inserted: 2}, {Inserted: 1, Duplicates: 1}},
+		expectedCode:    codes.OK,
+		expectedResponse: &grpcServer.ImportArticlesResponse{
+			Inserted:   3,
+			Rejected:   2,
+			Duplicates: 1,
+			Errors: []*grpcServer.ImportError{
+				{Index: 1, Message: errInvalidData},
+				{Index: 4, Message: errInvalidData},
+			},
+		},
+	}, {
+		name: "internal server error",
+		mockChunks: [][]repository.Article{
+			{{Name: "a", Rating: 1}, {Name: "c", Rating: 1}},
+		},
+		mockReturnValue: []repository.ImportResult{{}},
+		mockError:       fmt.Errorf("connection refused"),
+		expectedCode:    codes.Internal,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			handler.importChunkSize = 2
+			grpcServer.RegisterArticleServiceServer(server, handler)
+			calls := make([]any, 0, len(tc.mockChunks))
+			for i, chunk := range tc.mockChunks {
+				calls = append(calls, mockRepo.EXPECT().Import(gomock.Any(), chunk).Return(tc.mockReturnValue[i], tc.mockError))
+			}
+			gomock.InOrder(calls...)
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+
+			client := grpcServer.NewArticleServiceClient(conn)
+			stream, err := client.ImportArticles(context.Background())
+			require.NoError(t, err)
+			for _, article := range articles {
+				if err = stream.Send(article); err != nil {
+					break
+				}
+			}
+			response, err := stream.CloseAndRecv()
+
+			if tc.expectedCode != codes.OK {
+				st, _ := status.FromError(err)
+				assert.Equal(t, tc.expectedCode, st.Code())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResponse.Inserted, response.Inserted)
+			assert.Equal(t, tc.expectedResponse.Rejected, response.Rejected)
+			assert.Equal(t, tc.expectedResponse.Duplicates, response.Duplicates)
+			require.Len(t, response.Errors, len(tc.expectedResponse.Errors))
+			for i, importError := range tc.expectedResponse.Errors {
+				assert.Equal(t, importError.Index, response.Errors[i].Index)
+				assert.Equal(t, importError.Message, response.Errors[i].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPIDocument generates the OpenAPI 3 document of routes from the
+// descriptors of their messages, named as protojson encodes them.
+func openAPIDocument(routes []gatewayRoute) ([]byte, error) {
+	schemas := make(map[string]any)
+	operations := make(map[string]any)
+	for _, route := range routes {
+		operation := map[string]any{
+			"operationId": route.rpc,
+			"summary":     route.summary,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content":     jsonContent(messageSchema(route.response.ProtoReflect().Descriptor(), schemas)),
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		}
+		request := route.request.ProtoReflect().Descriptor()
+		if route.body {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(messageSchema(request, schemas)),
+			}
+		}
+		var parameters []any
+		for _, name := range route.queryParams {
+			field := request.Fields().ByName(protoreflect.Name(name))
+			parameters = append(parameters, map[string]any{
+				"name":     name,
+				"in":       "query",
+				"required": name == "id",
+				"schema":   fieldSchema(field, schemas),
+			})
+		}
+		if parameters != nil {
+			operation["parameters"] = parameters
+		}
+		operations[strings.ToLower(route.method)] = operation
+	}
+
+	schemas["Error"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"code":    map[string]any{"type": "string", "description": "gRPC status code"},
+			"message": map[string]any{"type": "string"},
+		},
+	}
+	return json.MarshalIndent(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "ArticleService",
+			"version": "1.0.0",
+		},
+		"paths":      map[string]any{entityPath: operations},
+		"components": map[string]any{"schemas": schemas},
+	}, "", "  ")
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// wellKnownSchemas are the schemas of the well-known types protojson encodes
+// specially.
+var wellKnownSchemas = map[protoreflect.FullName]map[string]any{
+	"google.protobuf.Timestamp": {"type": "string", "format": "date-time"},
+	"google.protobuf.Duration":  {"type": "string"},
+	"google.protobuf.FieldMask": {"type": "string", "description": "Comma separated field paths"},
+	"google.protobuf.Empty":     {"type": "object"},
+}
+
+// messageSchema returns a reference to the schema of message, adding it and
+// the schemas of its fields to schemas.
+func messageSchema(message protoreflect.MessageDescriptor, schemas map[string]any) map[string]any {
+	if schema, ok := wellKnownSchemas[message.FullName()]; ok {
+		return schema
+	}
+	name := string(message.Name())
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := schemas[name]; ok {
+		return ref
+	}
+
+	properties := make(map[string]any)
+	schema := map[string]any{"type": "object", "properties": properties}
+	schemas[name] = schema
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		properties[field.JSONName()] = fieldSchema(field, schemas)
+	}
+	return ref
+}
+
+func fieldSchema(field protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	var schema map[string]any
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		schema = map[string]any{"type": "boolean"}
+	case protoreflect.StringKind:
+		schema = map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		schema = map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		schema = map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson encodes 64-bit integers as strings.
+		schema = map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		schema = map[string]any{"type": "number"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		schema = map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		schema = messageSchema(field.Message(), schemas)
+	}
+	if field.IsList() {
+		return map[string]any{"type": "array", "items": schema}
+	}
+	return schema
+}
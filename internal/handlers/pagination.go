@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// defaultPageTokenSecret is a random key of the process, used until
+// SetPageTokenSecret is called. Tokens signed with it are only accepted by the
+// process that issued them.
+var defaultPageTokenSecret = randomPageTokenSecret()
+
+func randomPageTokenSecret() []byte {
+	secret := make([]byte, sha256.Size)
+	if _, err := rand.Read(secret); err != nil {
+		panic("failed to generate page token secret: " + err.Error())
+	}
+	return secret
+}
+
+var (
+	errPageSizeNegative = errors.New("page size must not be negative")
+	errPageTokenFormat  = errors.New("malformed page token")
+	errPageTokenDigest  = errors.New("page token signature mismatch")
+	errPageTokenQuery   = errors.New("page token was issued for a different query")
+)
+
+// pageToken is the cursor carried between ListArticles calls. Page tokens are
+// signed so that clients can treat them as opaque and cannot forge arbitrary
+// positions. Query pins a token to the request it was issued for, because a
+// cursor is meaningless under a different filter or ordering.
+type pageToken struct {
+	Query  string                `json:"query"`
+	Cursor repository.ListCursor `json:"cursor"`
+}
+
+// searchPageToken is the SearchArticles counterpart of pageToken.
+type searchPageToken struct {
+	Query  string                  `json:"query"`
+	Cursor repository.SearchCursor `json:"cursor"`
+}
+
+// historyPageToken is the GetArticleHistory counterpart of pageToken. Version
+// is the version of the last revision of the previous page.
+type historyPageToken struct {
+	Query   string `json:"query"`
+	Version int64  `json:"version"`
+}
+
+// queryDigest fingerprints the non-paging fields of a request. Callers pass a
+// copy of the request with page_size and page_token left unset.
+func queryDigest(request proto.Message) (string, error) {
+	query, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(query)
+	return base64.RawURLEncoding.EncodeToString(digest[:16]), nil
+}
+
+func normalizePageSize(size int32) (int, error) {
+	switch {
+	case size < 0:
+		return 0, errPageSizeNegative
+	case size == 0:
+		return defaultPageSize, nil
+	case size > maxPageSize:
+		return maxPageSize, nil
+	}
+	return int(size), nil
+}
+
+func encodePageToken(secret []byte, token interface{}) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signPageToken(secret, payload)), nil
+}
+
+func decodePageToken(secret []byte, raw string, token interface{}) error {
+	encodedPayload, encodedDigest, found := strings.Cut(raw, ".")
+	if !found {
+		return errPageTokenFormat
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return errPageTokenFormat
+	}
+	digest, err := base64.RawURLEncoding.DecodeString(encodedDigest)
+	if err != nil {
+		return errPageTokenFormat
+	}
+	if !hmac.Equal(digest, signPageToken(secret, payload)) {
+		return errPageTokenDigest
+	}
+	if err = json.Unmarshal(payload, token); err != nil {
+		return errPageTokenFormat
+	}
+	return nil
+}
+
+func signPageToken(secret []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// principalMetadataKey is the request metadata naming the identity a call is
+// made on behalf of. The service does not authenticate it; it is recorded in
+// article revisions as sent.
+const principalMetadataKey = "x-principal"
+
+// PrincipalInterceptor passes the principal of a unary call on to the
+// repository.
+func PrincipalInterceptor(ctx context.Context, request interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withPrincipal(ctx), request)
+}
+
+// StreamPrincipalInterceptor is the streaming counterpart of
+// PrincipalInterceptor.
+func StreamPrincipalInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &principalStream{ServerStream: stream, ctx: withPrincipal(stream.Context())})
+}
+
+func withPrincipal(ctx context.Context) context.Context {
+	if values := metadata.ValueFromIncomingContext(ctx, principalMetadataKey); len(values) > 0 {
+		return repository.WithPrincipal(ctx, values[0])
+	}
+	return ctx
+}
+
+// principalStream replaces the context of a stream with one carrying the
+// principal.
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *principalStream) Context() context.Context {
+	return stream.ctx
+}
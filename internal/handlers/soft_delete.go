@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (handler *GrpcArticleHandler) RestoreArticle(ctx context.Context, request *grpcServer.RestoreArticleRequest) (*emptypb.Empty, error) {
+	err := handler.repo.Restore(ctx, request.Id)
+	if err != nil {
+		return nil, tombstoneError(err, errArticleRestore)
+	}
+
+	return new(emptypb.Empty), nil
+}
+
+func (handler *GrpcArticleHandler) PurgeArticle(ctx context.Context, request *grpcServer.PurgeArticleRequest) (*emptypb.Empty, error) {
+	err := handler.repo.Purge(ctx, request.Id)
+	if err != nil {
+		return nil, tombstoneError(err, errArticlePurge)
+	}
+
+	return new(emptypb.Empty), nil
+}
+
+// tombstoneError maps errors of operations that only apply to soft-deleted
+// articles.
+func tombstoneError(err error, errPrefix string) error {
+	switch {
+	case errors.Is(err, repository.ErrArticalNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrArticleNotDeleted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, errPrefix+err.Error())
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	mock_kafka_interface "github.com/NRKA/gRPC-Server/internal/kafka/mocks"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	mock_repository "github.com/NRKA/gRPC-Server/internal/repository/mocks"
+	"github.com/NRKA/gRPC-Server/pkg/grpcServer"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestArticleHandler_RestoreAndPurge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		purge        bool
+		mockError    error
+		expectedCode codes.Code
+	}{{
+		name:         "restore",
+		expectedCode: codes.OK,
+	}, {
+		name:         "restore live article",
+		mockError:    repository.ErrArticleNotDeleted,
+		expectedCode: codes.FailedPrecondition,
+	}, {
+		name:         "purge",
+		purge:        true,
+		expectedCode: codes.OK,
+	}, {
+		name:         "purge missing article",
+		purge:        true,
+		mockError:    repository.ErrArticalNotFound,
+		expectedCode: codes.NotFound,
+	}, {
+		name:         "purge internal server error",
+		purge:        true,
+		mockError:    fmt.Errorf("connection refused"),
+		expectedCode: codes.Internal,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mock_repository.NewMockArticleInterface(ctrl)
+			mockKafka := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+
+			server := grpc.NewServer()
+			handler := NewGrpcArticleHandler(mockRepo, mockKafka)
+			grpcServer.RegisterArticleServiceServer(server, handler)
+
+			conn, closeConnAndServer := setupGRPCConnection(t, server)
+			defer closeConnAndServer()
+			client := grpcServer.NewArticleServiceClient(conn)
+
+			// act
+			var err error
+			if tc.purge {
+				mockRepo.EXPECT().Purge(gomock.Any(), int64(1)).Return(tc.mockError)
+				_, err = client.PurgeArticle(context.Background(), &grpcServer.PurgeArticleRequest{Id: 1})
+			} else {
+				mockRepo.EXPECT().Restore(gomock.Any(), int64(1)).Return(tc.mockError)
+				_, err = client.RestoreArticle(context.Background(), &grpcServer.RestoreArticleRequest{Id: 1})
+			}
+
+			// assert
+			st, _ := status.FromError(err)
+			assert.Equal(t, tc.expectedCode, st.Code())
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	DefaultInterval = 5 * time.Second
+	checkTimeout    = 2 * time.Second
+)
+
+// Checker checks one dependency of the server.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (checker CheckerFunc) Check(ctx context.Context) error {
+	return checker(ctx)
+}
+
+// Monitor runs the checks of the dependencies in the background and reports
+// the services as serving while all of them pass. The state is served by the
+// standard gRPC health service and by the HTTP handler.
+type Monitor struct {
+	server   *health.Server
+	services []string
+	interval time.Duration
+
+	mu       sync.RWMutex
+	checks   map[string]Checker
+	failures map[string]string
+	// checked is whether the checks ran since the start and until the
+	// shutdown.
+	checked bool
+}
+
+// NewMonitor creates a monitor of services, which are reported as not serving
+// until the first checks passed. The overall health of the server is reported
+// for the empty service name.
+func NewMonitor(interval time.Duration, services ...string) *Monitor {
+	monitor := &Monitor{
+		server:   health.NewServer(),
+		services: append([]string{""}, services...),
+		interval: interval,
+		checks:   make(map[string]Checker),
+		failures: make(map[string]string),
+	}
+	monitor.setStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	return monitor
+}
+
+// Server returns the grpc_health_v1 service to register.
+func (monitor *Monitor) Server() grpc_health_v1.HealthServer {
+	return monitor.server
+}
+
+// AddCheck adds the check of a dependency.
+func (monitor *Monitor) AddCheck(name string, checker Checker) {
+	monitor.mu.Lock()
+	defer monitor.mu.Unlock()
+	monitor.checks[name] = checker
+}
+
+// Run checks the dependencies until ctx is done. The services are then
+// reported as not serving for good, so clients move away while the server
+// shuts down.
+func (monitor *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(monitor.interval)
+	defer ticker.Stop()
+
+	for {
+		monitor.check(ctx)
+		select {
+		case <-ctx.Done():
+			monitor.mu.Lock()
+			monitor.checked = false
+			monitor.mu.Unlock()
+			monitor.server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (monitor *Monitor) check(ctx context.Context) {
+	monitor.mu.RLock()
+	checks := make(map[string]Checker, len(monitor.checks))
+	for name, checker := range monitor.checks {
+		checks[name] = checker
+	}
+	monitor.mu.RUnlock()
+
+	failures := make(map[string]string)
+	for name, checker := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := checker.Check(checkCtx)
+		cancel()
+		if err != nil {
+			failures[name] = err.Error()
+		}
+	}
+
+	monitor.mu.Lock()
+	for name, failure := range failures {
+		if _, failed := monitor.failures[name]; !failed {
+			logger.Errorf(ctx, "health check %s failed: %s", name, failure)
+		}
+	}
+	for name := range monitor.failures {
+		if _, failed := failures[name]; !failed {
+			logger.Infof(ctx, "health check %s recovered", name)
+		}
+	}
+	monitor.failures = failures
+	monitor.checked = true
+	monitor.mu.Unlock()
+
+	if len(failures) > 0 {
+		monitor.setStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	} else {
+		monitor.setStatus(grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+}
+
+func (monitor *Monitor) setStatus(status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	for _, service := range monitor.services {
+		monitor.server.SetServingStatus(service, status)
+	}
+}
+
+// readiness is the body of the /readyz response.
+type readiness struct {
+	Ready  bool              `json:"ready"`
+	Failed map[string]string `json:"failed,omitempty"`
+	Checks []string          `json:"checks"`
+}
+
+// Handler serves /healthz, which succeeds while the process is up, and
+// /readyz, which succeeds while all the checks pass.
+func (monitor *Monitor) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		monitor.mu.RLock()
+		response := readiness{Ready: monitor.checked && len(monitor.failures) == 0}
+		if len(monitor.failures) > 0 {
+			response.Failed = make(map[string]string, len(monitor.failures))
+			for name, failure := range monitor.failures {
+				response.Failed[name] = failure
+			}
+		}
+		for name := range monitor.checks {
+			response.Checks = append(response.Checks, name)
+		}
+		monitor.mu.RUnlock()
+		sort.Strings(response.Checks)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !response.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	return mux
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const articleService = "ArticleService"
+
+func status(t *testing.T, monitor *Monitor, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	response, err := monitor.Server().Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return response.Status
+}
+
+func readyz(t *testing.T, monitor *Monitor) (int, readiness) {
+	recorder := httptest.NewRecorder()
+	monitor.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var response readiness
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	return recorder.Code, response
+}
+
+func TestMonitor(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	monitor := NewMonitor(time.Hour, articleService)
+	var kafkaErr error
+	monitor.AddCheck("postgres", CheckerFunc(func(context.Context) error { return nil }))
+	monitor.AddCheck("kafka", CheckerFunc(func(context.Context) error { return kafkaErr }))
+
+	// act & assert
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, status(t, monitor, articleService))
+	code, _ := readyz(t, monitor)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	monitor.check(context.Background())
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, status(t, monitor, articleService))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, status(t, monitor, ""))
+	code, response := readyz(t, monitor)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, readiness{Ready: true, Checks: []string{"kafka", "postgres"}}, response)
+
+	kafkaErr = errors.New("broker unavailable")
+	monitor.check(context.Background())
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, status(t, monitor, articleService))
+	code, response = readyz(t, monitor)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, map[string]string{"kafka": "broker unavailable"}, response.Failed)
+}
+
+func TestMonitor_Run_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	monitor := NewMonitor(time.Hour, articleService)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	monitor.Run(ctx)
+
+	// assert
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, status(t, monitor, articleService))
+	code, _ := readyz(t, monitor)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	recorder := httptest.NewRecorder()
+	monitor.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
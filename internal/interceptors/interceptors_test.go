@@ -0,0 +1,122 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func chain(ctx context.Context, handler grpc.UnaryHandler) (interface{}, error) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/ArticleService/GetArticle"}
+	return UnaryLogging(ctx, "request", info, func(ctx context.Context, request interface{}) (interface{}, error) {
+		return UnaryTracing(ctx, request, info, handler)
+	})
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	// arrange
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := logger.ToContext(context.Background(), zap.New(core))
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+	parent := tracer.StartSpan("client")
+	md := metadata.Pairs(RequestIDMetadataKey, "request-1")
+	require.NoError(t, tracer.Inject(parent.Context(), opentracing.TextMap, metadataCarrier(md)))
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	// act
+	_, err := chain(ctx, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		logger.Infof(ctx, "handling")
+		return nil, status.Error(codes.Internal, "database is down")
+	})
+
+	// assert
+	assert.Equal(t, codes.Internal, status.Code(err))
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "/ArticleService/GetArticle", entries[0].ContextMap()["method"])
+	assert.Equal(t, "request-1", entries[0].ContextMap()["request_id"])
+	assert.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	assert.Equal(t, "Internal", entries[1].ContextMap()["code"])
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, parent.Context().(mocktracer.MockSpanContext).SpanID, spans[0].ParentID)
+	assert.Equal(t, true, spans[0].Tag("error"))
+	assert.Equal(t, "Internal", spans[0].Tag("grpc.code"))
+	assert.Equal(t, "request-1", spans[0].Tag("request_id"))
+}
+
+func TestUnaryLogging_GeneratesRequestID(t *testing.T) {
+	// arrange
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := logger.ToContext(context.Background(), zap.New(core))
+	var requestID string
+
+	// act
+	_, err := chain(ctx, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		requestID = RequestIDFromContext(ctx)
+		return nil, status.Error(codes.NotFound, "article not found")
+	})
+
+	// assert
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Len(t, requestID, 32)
+	require.Len(t, logs.All(), 1)
+	assert.Equal(t, zapcore.InfoLevel, logs.All()[0].Level)
+}
+
+func TestUnaryRecovery(t *testing.T) {
+	// arrange
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := logger.ToContext(context.Background(), zap.New(core))
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/ArticleService/GetArticle"}
+	panicsBefore := PanicCount()
+
+	// act
+	_, err := chain(ctx, func(ctx context.Context, request interface{}) (interface{}, error) {
+		return UnaryRecovery(ctx, request, info, func(context.Context, interface{}) (interface{}, error) {
+			var article *struct{ Name string }
+			return article.Name, nil
+		})
+	})
+
+	// assert
+	assert.Equal(t, status.Error(codes.Internal, errPanic), err)
+	assert.Equal(t, panicsBefore+1, PanicCount())
+	require.Len(t, logs.FilterMessage("handler panicked").All(), 1)
+	assert.Contains(t, logs.FilterMessage("handler panicked").All()[0].ContextMap()["stack"], "TestUnaryRecovery")
+	require.Len(t, tracer.FinishedSpans(), 1)
+	assert.Equal(t, true, tracer.FinishedSpans()[0].Tag("error"))
+}
+
+func TestStreamRecovery(t *testing.T) {
+	// arrange
+	stream := &serverStream{ctx: context.Background()}
+	info := &grpc.StreamServerInfo{FullMethod: "/ArticleService/WatchArticles"}
+
+	// act
+	err := StreamRecovery(nil, stream, info, func(interface{}, grpc.ServerStream) error {
+		panic("watch hub is nil")
+	})
+
+	// assert
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "watch hub")
+}
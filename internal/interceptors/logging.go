@@ -0,0 +1,71 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// serverErrors are the codes logged as errors. The others are the client's
+// fault and are logged as info.
+var serverErrors = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.DeadlineExceeded: true,
+	codes.Unimplemented:    true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DataLoss:         true,
+}
+
+// UnaryLogging puts a logger with the method, peer and request id of the call
+// in its context and logs the status code and latency of the call.
+func UnaryLogging(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = withLogger(ctx, info.FullMethod)
+	start := time.Now()
+	response, err := handler(ctx, request)
+	logCall(ctx, start, err)
+	return response, err
+}
+
+// StreamLogging is UnaryLogging for streaming calls.
+func StreamLogging(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withLogger(stream.Context(), info.FullMethod)
+	start := time.Now()
+	err := handler(srv, withContext(stream, ctx))
+	logCall(ctx, start, err)
+	return err
+}
+
+func withLogger(ctx context.Context, method string) context.Context {
+	ctx = withRequestID(ctx)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("request_id", RequestIDFromContext(ctx)),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	return logger.ToContext(ctx, logger.FromContext(ctx).With(fields...))
+}
+
+func logCall(ctx context.Context, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("code", code.String()),
+		zap.Duration("latency", time.Since(start)),
+	}
+	if serverErrors[code] {
+		logger.FromContext(ctx).Error("call failed", append(fields, zap.Error(err))...)
+		return
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logger.FromContext(ctx).Info("call finished", fields...)
+}
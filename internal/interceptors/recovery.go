@@ -0,0 +1,63 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errPanic is all the client learns about a panic. The details are logged.
+const errPanic = "internal server error"
+
+var panics atomic.Int64
+
+// PanicCount returns the number of panics recovered since the start.
+func PanicCount() int64 {
+	return panics.Load()
+}
+
+// UnaryRecovery turns a panic in the handler into a codes.Internal error. It
+// has to come after UnaryLogging and UnaryTracing in the chain, so the panic is
+// logged with the request fields and recorded on the span of the call.
+func UnaryRecovery(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = recovered(ctx, value)
+		}
+	}()
+	return handler(ctx, request)
+}
+
+// StreamRecovery is UnaryRecovery for streaming calls.
+func StreamRecovery(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = recovered(stream.Context(), value)
+		}
+	}()
+	return handler(srv, stream)
+}
+
+func recovered(ctx context.Context, value interface{}) error {
+	panics.Add(1)
+	stack := debug.Stack()
+	logger.FromContext(ctx).Error("handler panicked",
+		zap.String("panic", fmt.Sprint(value)),
+		zap.ByteString("stack", stack),
+	)
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(log.String("event", "panic"), log.String("panic", fmt.Sprint(value)))
+	}
+	return status.Error(codes.Internal, errPanic)
+}
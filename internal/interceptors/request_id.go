@@ -0,0 +1,42 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDMetadataKey is the metadata carrying the id of a request. An id
+// sent by the client is kept, otherwise one is generated. It is returned in
+// the response header either way.
+const RequestIDMetadataKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the id of the request being served.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// withRequestID adds the request id to ctx and sends it back to the client.
+func withRequestID(ctx context.Context) context.Context {
+	id := ""
+	if values := metadata.ValueFromIncomingContext(ctx, RequestIDMetadataKey); len(values) > 0 {
+		id = values[0]
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, id))
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,21 @@
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// serverStream replaces the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *serverStream) Context() context.Context {
+	return stream.ctx
+}
+
+func withContext(stream grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &serverStream{ServerStream: stream, ctx: ctx}
+}
@@ -0,0 +1,75 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryTracing starts the server span of a call as a child of the span sent by
+// the client in the metadata, if any. The span is tagged with the status code
+// and marked as failed when the call returns an error.
+func UnaryTracing(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	span, ctx := startSpan(ctx, info.FullMethod)
+	defer span.Finish()
+	response, err := handler(ctx, request)
+	finishSpan(span, err)
+	return response, err
+}
+
+// StreamTracing is UnaryTracing for streaming calls.
+func StreamTracing(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	span, ctx := startSpan(stream.Context(), info.FullMethod)
+	defer span.Finish()
+	err := handler(srv, withContext(stream, ctx))
+	finishSpan(span, err)
+	return err
+}
+
+func startSpan(ctx context.Context, method string) (opentracing.Span, context.Context) {
+	tracer := opentracing.GlobalTracer()
+	md, _ := metadata.FromIncomingContext(ctx)
+	parent, err := tracer.Extract(opentracing.TextMap, metadataCarrier(md))
+	options := []opentracing.StartSpanOption{ext.SpanKindRPCServer, opentracing.Tag{Key: string(ext.Component), Value: "gRPC"}}
+	if err == nil {
+		options = append(options, ext.RPCServerOption(parent))
+	}
+	span := tracer.StartSpan(method, options...)
+	if id := RequestIDFromContext(ctx); id != "" {
+		span.SetTag("request_id", id)
+	}
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+func finishSpan(span opentracing.Span, err error) {
+	span.SetTag("grpc.code", status.Code(err).String())
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(log.Error(err))
+	}
+}
+
+// metadataCarrier reads span contexts from gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (carrier metadataCarrier) ForeachKey(handler func(key, value string) error) error {
+	for key, values := range carrier {
+		for _, value := range values {
+			if err := handler(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (carrier metadataCarrier) Set(key, value string) {
+	key = strings.ToLower(key)
+	carrier[key] = append(carrier[key], value)
+}
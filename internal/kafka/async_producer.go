@@ -0,0 +1,255 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+)
+
+// ErrBufferFull is returned by AsyncKafkaProducer.SendEvent when the buffer is
+// full and the backpressure policy is BackpressureDrop.
+var ErrBufferFull = errors.New("event buffer is full")
+
+// ErrProducerClosed is returned by AsyncKafkaProducer.SendEvent after Close.
+var ErrProducerClosed = errors.New("producer is closed")
+
+// Backpressure is what SendEvent does when the buffer is full.
+type Backpressure int
+
+const (
+	// BackpressureBlock waits for room in the buffer.
+	BackpressureBlock Backpressure = iota
+	// BackpressureDrop drops the event and returns ErrBufferFull.
+	BackpressureDrop
+)
+
+// ParseBackpressure parses "block" or "drop".
+func ParseBackpressure(value string) (Backpressure, error) {
+	switch value {
+	case "block":
+		return BackpressureBlock, nil
+	case "drop":
+		return BackpressureDrop, nil
+	}
+	return 0, fmt.Errorf("unknown backpressure policy %q", value)
+}
+
+// ParseCompression parses "none", "gzip", "snappy", "lz4" or "zstd".
+func ParseCompression(value string) (sarama.CompressionCodec, error) {
+	switch value {
+	case "none":
+		return sarama.CompressionNone, nil
+	case "gzip":
+		return sarama.CompressionGZIP, nil
+	case "snappy":
+		return sarama.CompressionSnappy, nil
+	case "lz4":
+		return sarama.CompressionLZ4, nil
+	case "zstd":
+		return sarama.CompressionZSTD, nil
+	}
+	return 0, fmt.Errorf("unknown compression %q", value)
+}
+
+// ParseAcks parses "none", "leader" or "all".
+func ParseAcks(value string) (sarama.RequiredAcks, error) {
+	switch value {
+	case "none":
+		return sarama.NoResponse, nil
+	case "leader":
+		return sarama.WaitForLocal, nil
+	case "all":
+		return sarama.WaitForAll, nil
+	}
+	return 0, fmt.Errorf("unknown acks level %q", value)
+}
+
+// AsyncProducerConfig configures an AsyncKafkaProducer.
+type AsyncProducerConfig struct {
+	Partitioner Partitioner
+	// Linger is how long a batch waits for more events before it is sent.
+	Linger time.Duration
+	// MaxBatchBytes sends a batch early once it reaches this size.
+	MaxBatchBytes int
+	Compression   sarama.CompressionCodec
+	// Idempotent makes the broker drop duplicates of retried batches. It
+	// requires Acks to be sarama.WaitForAll.
+	Idempotent bool
+	Acks       sarama.RequiredAcks
+	// BufferSize is the number of events waiting to be batched.
+	BufferSize   int
+	Backpressure Backpressure
+	// FlushTimeout bounds how long Close waits for buffered events.
+	FlushTimeout time.Duration
+}
+
+// DefaultAsyncProducerConfig returns the configuration used when none of the
+// settings are overridden.
+func DefaultAsyncProducerConfig() AsyncProducerConfig {
+	return AsyncProducerConfig{
+		Partitioner:   PartitionerHash,
+		Linger:        10 * time.Millisecond,
+		MaxBatchBytes: 1 << 20,
+		Compression:   sarama.CompressionSnappy,
+		Acks:          sarama.WaitForLocal,
+		BufferSize:    4096,
+		Backpressure:  BackpressureBlock,
+		FlushTimeout:  10 * time.Second,
+	}
+}
+
+func (config AsyncProducerConfig) saramaConfig() (*sarama.Config, error) {
+	saramaConfig, err := newProducerConfig(config.Partitioner)
+	if err != nil {
+		return nil, err
+	}
+	saramaConfig.Producer.Return.Successes = false
+	saramaConfig.Producer.Return.Errors = true
+	saramaConfig.Producer.Flush.Frequency = config.Linger
+	saramaConfig.Producer.Flush.Bytes = config.MaxBatchBytes
+	saramaConfig.Producer.Compression = config.Compression
+	saramaConfig.Producer.RequiredAcks = config.Acks
+	saramaConfig.Producer.Idempotent = config.Idempotent
+	saramaConfig.ChannelBufferSize = config.BufferSize
+	if config.Idempotent {
+		saramaConfig.Net.MaxOpenRequests = 1
+	}
+	if config.Idempotent || config.Compression == sarama.CompressionZSTD {
+		saramaConfig.Version = sarama.V2_1_0_0
+	}
+	if err = saramaConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid producer config: %w", err)
+	}
+	return saramaConfig, nil
+}
+
+// AsyncKafkaProducer batches events in the background instead of waiting for
+// the broker on every SendEvent. Delivery errors are logged, so it is meant
+// for events that may be lost, not for the outbox relay.
+type AsyncKafkaProducer struct {
+	producer     sarama.AsyncProducer
+	encoding     Encoding
+	backpressure Backpressure
+	flushTimeout time.Duration
+
+	mu     sync.Mutex
+	closed bool
+	// done is closed by Close, which releases the sends blocked on a full
+	// buffer.
+	done chan struct{}
+	// senders are the sends in progress, which must be over before the input
+	// is closed.
+	senders sync.WaitGroup
+	errors  sync.WaitGroup
+}
+
+func NewAsyncKafkaProducer(brokerAddress string, config AsyncProducerConfig) (*AsyncKafkaProducer, error) {
+	saramaConfig, err := config.saramaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewAsyncProducer([]string{brokerAddress}, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create producer: %v", err)
+	}
+
+	return newAsyncKafkaProducer(producer, config), nil
+}
+
+func newAsyncKafkaProducer(producer sarama.AsyncProducer, config AsyncProducerConfig) *AsyncKafkaProducer {
+	asyncProducer := &AsyncKafkaProducer{
+		producer:     producer,
+		backpressure: config.Backpressure,
+		flushTimeout: config.FlushTimeout,
+		done:         make(chan struct{}),
+	}
+	asyncProducer.errors.Add(1)
+	go asyncProducer.logErrors()
+	return asyncProducer
+}
+
+// SetEncoding selects the wire format of events, protobuf by default.
+func (producer *AsyncKafkaProducer) SetEncoding(encoding Encoding) {
+	producer.encoding = encoding
+}
+
+// SendEvent buffers event. It returns once the event is buffered, before it
+// reaches Kafka. A send blocked on a full buffer fails once the producer is
+// closed.
+func (producer *AsyncKafkaProducer) SendEvent(topic string, event Event) error {
+	message, err := newMessage(topic, event, producer.encoding)
+	if err != nil {
+		return err
+	}
+
+	if !producer.startSend() {
+		return ErrProducerClosed
+	}
+	defer producer.senders.Done()
+	if producer.backpressure == BackpressureDrop {
+		select {
+		case producer.producer.Input() <- message:
+			return nil
+		default:
+			return ErrBufferFull
+		}
+	}
+	select {
+	case producer.producer.Input() <- message:
+		return nil
+	case <-producer.done:
+		return ErrProducerClosed
+	}
+}
+
+// startSend registers a send unless the producer is closed.
+func (producer *AsyncKafkaProducer) startSend() bool {
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	if producer.closed {
+		return false
+	}
+	producer.senders.Add(1)
+	return true
+}
+
+// Close sends the buffered events and stops the producer. It waits for them
+// at most the flush timeout.
+func (producer *AsyncKafkaProducer) Close() error {
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		producer.mu.Lock()
+		if producer.closed {
+			producer.mu.Unlock()
+			return
+		}
+		producer.closed = true
+		close(producer.done)
+		producer.mu.Unlock()
+
+		producer.senders.Wait()
+		producer.producer.AsyncClose()
+		producer.errors.Wait()
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-time.After(producer.flushTimeout):
+		return fmt.Errorf("failed to flush producer in %v", producer.flushTimeout)
+	}
+}
+
+func (producer *AsyncKafkaProducer) logErrors() {
+	defer producer.errors.Done()
+	for err := range producer.producer.Errors() {
+		logger.Errorf(context.Background(), "failed to send message to Kafka: %v", err)
+	}
+}
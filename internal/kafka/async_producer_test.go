@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncKafkaProducer_SendEvent(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	config := DefaultAsyncProducerConfig()
+	saramaConfig, err := config.saramaConfig()
+	require.NoError(t, err)
+	asyncProducer := mocks.NewAsyncProducer(t, saramaConfig)
+	asyncProducer.ExpectInputWithMessageCheckerFunctionAndSucceed(func(message *sarama.ProducerMessage) error {
+		key, err := message.Key.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, "10", string(key))
+		return nil
+	})
+	asyncProducer.ExpectInputAndFail(sarama.ErrRequestTimedOut)
+	producer := newAsyncKafkaProducer(asyncProducer, config)
+
+	// act
+	err1 := producer.SendEvent("crud", Event{Type: events.EventType_EVENT_TYPE_ARTICLE_VIEWED, AggregateID: 10})
+	err2 := producer.SendEvent("crud", Event{Type: events.EventType_EVENT_TYPE_ARTICLE_VIEWED, AggregateID: 11})
+	closeErr := producer.Close()
+
+	// assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, closeErr)
+	assert.ErrorIs(t, producer.SendEvent("crud", Event{AggregateID: 10}), ErrProducerClosed)
+}
+
+// blockedProducer never takes messages out of its buffer.
+type blockedProducer struct {
+	sarama.AsyncProducer
+	input  chan *sarama.ProducerMessage
+	errors chan *sarama.ProducerError
+}
+
+func (producer *blockedProducer) Input() chan<- *sarama.ProducerMessage {
+	return producer.input
+}
+
+func (producer *blockedProducer) Errors() <-chan *sarama.ProducerError {
+	return producer.errors
+}
+
+func (producer *blockedProducer) AsyncClose() {}
+
+func TestAsyncKafkaProducer_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	config := DefaultAsyncProducerConfig()
+	config.Backpressure = BackpressureDrop
+	config.FlushTimeout = 10 * time.Millisecond
+	producer := newAsyncKafkaProducer(&blockedProducer{
+		input:  make(chan *sarama.ProducerMessage, 1),
+		errors: make(chan *sarama.ProducerError),
+	}, config)
+
+	// act
+	err1 := producer.SendEvent("crud", Event{AggregateID: 10})
+	err2 := producer.SendEvent("crud", Event{AggregateID: 11})
+
+	// assert
+	assert.NoError(t, err1)
+	assert.ErrorIs(t, err2, ErrBufferFull)
+	assert.Error(t, producer.Close())
+}
+
+func TestAsyncProducerConfig_IdempotenceRequiresAllAcks(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultAsyncProducerConfig()
+	config.Idempotent = true
+	_, err := config.saramaConfig()
+	assert.Error(t, err)
+
+	config.Acks, err = ParseAcks("all")
+	require.NoError(t, err)
+	config.Compression, err = ParseCompression("zstd")
+	require.NoError(t, err)
+	_, err = config.saramaConfig()
+	assert.NoError(t, err)
+}
+
+func TestAsyncKafkaProducer_CloseReleasesBlockedSends(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	config := DefaultAsyncProducerConfig()
+	config.FlushTimeout = 100 * time.Millisecond
+	producer := newAsyncKafkaProducer(&blockedProducer{
+		input:  make(chan *sarama.ProducerMessage),
+		errors: make(chan *sarama.ProducerError),
+	}, config)
+	sent := make(chan error)
+	go func() {
+		sent <- producer.SendEvent("crud", Event{AggregateID: 10})
+	}()
+
+	// act
+	time.Sleep(10 * time.Millisecond)
+	closeErr := producer.Close()
+
+	// assert
+	assert.ErrorIs(t, <-sent, ErrProducerClosed)
+	assert.Error(t, closeErr, "the errors of the blocked producer are never closed")
+	assert.ErrorIs(t, producer.SendEvent("crud", Event{AggregateID: 11}), ErrProducerClosed)
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker stops calls to Kafka after too many consecutive failures.
+// Once open it lets a single call through every open timeout to probe whether
+// Kafka is back, and closes again when that call succeeds.
+type CircuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(threshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         time.Now,
+	}
+}
+
+// Allow reports whether a call may be made. A call it allowed must be followed
+// by Success or Failure.
+func (breaker *CircuitBreaker) Allow() bool {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	if breaker.failures < breaker.threshold {
+		return true
+	}
+	if breaker.probing || breaker.now().Sub(breaker.openedAt) < breaker.openTimeout {
+		return false
+	}
+	breaker.probing = true
+	return true
+}
+
+// Open reports whether calls are stopped.
+func (breaker *CircuitBreaker) Open() bool {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	return breaker.failures >= breaker.threshold
+}
+
+func (breaker *CircuitBreaker) Success() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	breaker.failures = 0
+	breaker.probing = false
+}
+
+func (breaker *CircuitBreaker) Failure() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	breaker.failures++
+	breaker.probing = false
+	if breaker.failures >= breaker.threshold {
+		breaker.openedAt = breaker.now()
+	}
+}
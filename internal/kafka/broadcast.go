@@ -0,0 +1,159 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+)
+
+const (
+	broadcastRestartBackoff    = time.Second
+	broadcastMaxRestartBackoff = time.Minute
+)
+
+// BroadcastConsumer reads all the partitions of a topic outside of any
+// consumer group, so every server sees every message. It starts at the newest
+// messages and commits no offsets, which suits state that only lives in the
+// process, such as the watcher hub.
+type BroadcastConsumer struct {
+	connect func() (sarama.Consumer, error)
+
+	mu sync.Mutex
+	// offsets are the next offsets of the partitions, so a restarted consumer
+	// continues where it failed.
+	offsets map[int32]int64
+}
+
+// NewBroadcastConsumer creates a consumer that connects once it runs.
+func NewBroadcastConsumer(brokerAddress string) *BroadcastConsumer {
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	return newBroadcastConsumer(func() (sarama.Consumer, error) {
+		return sarama.NewConsumer([]string{brokerAddress}, config)
+	})
+}
+
+func newBroadcastConsumer(connect func() (sarama.Consumer, error)) *BroadcastConsumer {
+	return &BroadcastConsumer{connect: connect, offsets: make(map[int32]int64)}
+}
+
+// Run passes the messages of topic to handler until ctx is done, restarting
+// with backoff after failures. Failures of handler are only logged.
+func (consumer *BroadcastConsumer) Run(ctx context.Context, topic string, handler MessageHandler) {
+	backoff := broadcastRestartBackoff
+	for {
+		err := consumer.consume(ctx, topic, handler)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Errorf(ctx, "broadcast consumer of %s failed, restarting in %v: %v", topic, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, broadcastMaxRestartBackoff)
+	}
+}
+
+func (consumer *BroadcastConsumer) consume(ctx context.Context, topic string, handler MessageHandler) error {
+	client, err := consumer.connect()
+	if err != nil {
+		return fmt.Errorf("failed to create Consumer: %w", err)
+	}
+	defer client.Close()
+
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return fmt.Errorf("failed to get partitions of %s: %w", topic, err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, partition := range partitions {
+		partitionConsumer, err := consumer.consumePartition(client, topic, partition)
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return err
+		}
+		wg.Add(1)
+		go func(partition int32) {
+			defer wg.Done()
+			defer partitionConsumer.Close()
+			err := consumer.consumeMessages(ctx, partitionConsumer, handler)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to consume partition %d: %w", partition, err)
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}(partition)
+	}
+	wg.Wait()
+	if firstErr == nil && ctx.Err() == nil {
+		return errors.New("partition consumers stopped")
+	}
+	return firstErr
+}
+
+// consumePartition starts at the next offset of partition, or at the newest
+// message when it was never read or its next offset is no longer retained.
+func (consumer *BroadcastConsumer) consumePartition(client sarama.Consumer, topic string, partition int32) (sarama.PartitionConsumer, error) {
+	offset, ok := consumer.offset(partition)
+	if ok {
+		partitionConsumer, err := client.ConsumePartition(topic, partition, offset)
+		if !errors.Is(err, sarama.ErrOffsetOutOfRange) {
+			return partitionConsumer, err
+		}
+		logger.Errorf(context.Background(), "offset %d of %s/%d is gone, skipping to the newest", offset, topic, partition)
+	}
+	return client.ConsumePartition(topic, partition, sarama.OffsetNewest)
+}
+
+func (consumer *BroadcastConsumer) consumeMessages(ctx context.Context, partitionConsumer sarama.PartitionConsumer, handler MessageHandler) error {
+	for {
+		select {
+		case message, ok := <-partitionConsumer.Messages():
+			if !ok {
+				return nil
+			}
+			if err := handler.HandleMessage(ctx, message); err != nil {
+				logger.Errorf(ctx, "failed to handle message %s/%d/%d: %v",
+					message.Topic, message.Partition, message.Offset, err)
+			}
+			consumer.setOffset(message.Partition, message.Offset+1)
+		case err, ok := <-partitionConsumer.Errors():
+			if !ok {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (consumer *BroadcastConsumer) offset(partition int32) (int64, bool) {
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	offset, ok := consumer.offsets[partition]
+	return offset, ok
+}
+
+func (consumer *BroadcastConsumer) setOffset(partition int32, offset int64) {
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	consumer.offsets[partition] = offset
+}
@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcastConsumer_Run(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	client := mocks.NewConsumer(t, nil)
+	client.SetTopicMetadata(map[string][]int32{"crud": {0, 1}})
+	client.ExpectConsumePartition("crud", 0, sarama.OffsetNewest).YieldMessage(&sarama.ConsumerMessage{Value: []byte("first")})
+	client.ExpectConsumePartition("crud", 1, sarama.OffsetNewest).YieldMessage(&sarama.ConsumerMessage{Value: []byte("second")})
+	consumer := newBroadcastConsumer(func() (sarama.Consumer, error) { return client, nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var received []string
+
+	// act
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		consumer.Run(ctx, "crud", MessageHandlerFunc(func(_ context.Context, message *sarama.ConsumerMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, string(message.Value))
+			return nil
+		}))
+	}()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	// assert
+	assert.ElementsMatch(t, []string{"first", "second"}, received)
+	_, ok := consumer.offset(1)
+	assert.True(t, ok, "a restart continues after the handled messages")
+}
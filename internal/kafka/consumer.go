@@ -2,63 +2,334 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
 	"sync"
+	"time"
 )
 
+// MessageHandler processes the messages read by KafkaConsumer. A message is
+// committed once HandleMessage returns, whether it failed or not, unless the
+// context was done by then. On shutdown the context of the message being
+// handled is done only after the drain timeout.
+type MessageHandler interface {
+	HandleMessage(ctx context.Context, message *sarama.ConsumerMessage) error
+}
+
+// MessageHandlerFunc adapts a function to MessageHandler.
+type MessageHandlerFunc func(ctx context.Context, message *sarama.ConsumerMessage) error
+
+func (handler MessageHandlerFunc) HandleMessage(ctx context.Context, message *sarama.ConsumerMessage) error {
+	return handler(ctx, message)
+}
+
+// ParseInitialOffset parses "newest" or "oldest".
+func ParseInitialOffset(value string) (int64, error) {
+	switch value {
+	case "newest":
+		return sarama.OffsetNewest, nil
+	case "oldest":
+		return sarama.OffsetOldest, nil
+	}
+	return 0, fmt.Errorf("unknown initial offset %q", value)
+}
+
+// ParseRebalanceStrategy parses "range", "roundrobin" or "sticky".
+func ParseRebalanceStrategy(value string) (sarama.BalanceStrategy, error) {
+	switch value {
+	case "range":
+		return sarama.NewBalanceStrategyRange(), nil
+	case "roundrobin":
+		return sarama.NewBalanceStrategyRoundRobin(), nil
+	case "sticky":
+		return sarama.NewBalanceStrategySticky(), nil
+	}
+	return nil, fmt.Errorf("unknown rebalance strategy %q", value)
+}
+
+// FailurePolicy is what KafkaConsumer.Run does when consuming fails.
+type FailurePolicy int
+
+const (
+	// FailureRestart joins the group again after a backoff.
+	FailureRestart FailurePolicy = iota
+	// FailureFailFast returns the error, so the caller can shut down.
+	FailureFailFast
+)
+
+// ParseFailurePolicy parses "restart" or "fail-fast".
+func ParseFailurePolicy(value string) (FailurePolicy, error) {
+	switch value {
+	case "restart":
+		return FailureRestart, nil
+	case "fail-fast":
+		return FailureFailFast, nil
+	}
+	return 0, fmt.Errorf("unknown failure policy %q", value)
+}
+
+// ConsumerConfig configures a KafkaConsumer.
+type ConsumerConfig struct {
+	// GroupID is shared by the replicas, which split the partitions between
+	// them.
+	GroupID string
+	// InitialOffset is where a group without a committed offset starts,
+	// sarama.OffsetNewest or sarama.OffsetOldest.
+	InitialOffset int64
+	Rebalance     sarama.BalanceStrategy
+	FailurePolicy FailurePolicy
+	// RestartBackoff is the first wait of FailureRestart. It doubles after
+	// every failure up to MaxRestartBackoff.
+	RestartBackoff    time.Duration
+	MaxRestartBackoff time.Duration
+	// DrainTimeout is how long the messages being handled when the consumer
+	// stops get to finish.
+	DrainTimeout time.Duration
+}
+
+// DefaultConsumerConfig returns the configuration of the consumer group
+// groupID when none of the other settings are overridden.
+func DefaultConsumerConfig(groupID string) ConsumerConfig {
+	return ConsumerConfig{
+		GroupID:           groupID,
+		InitialOffset:     sarama.OffsetNewest,
+		Rebalance:         sarama.NewBalanceStrategyRange(),
+		FailurePolicy:     FailureRestart,
+		RestartBackoff:    time.Second,
+		MaxRestartBackoff: time.Minute,
+		DrainTimeout:      10 * time.Second,
+	}
+}
+
+// LagObserver is told how far the consumer is behind on a partition after each
+// message, and to forget the partition once it is no longer claimed.
+type LagObserver interface {
+	ObserveLag(topic string, partition int32, lag int64)
+	ForgetLag(topic string, partition int32)
+}
+
+// KafkaConsumer reads topics as a member of a consumer group. Offsets are
+// committed, so a restarted consumer continues where the group stopped.
 type KafkaConsumer struct {
-	Consumer sarama.Consumer
+	connect func() (sarama.ConsumerGroup, error)
+	config  ConsumerConfig
+	lag     LagObserver
+	errors  sync.WaitGroup
+
+	mu    sync.Mutex
+	group sarama.ConsumerGroup
+	// err is the failure Run is restarting after, until the group is joined
+	// again.
+	err error
+}
+
+// NewKafkaConsumer creates a consumer that joins the group once it runs, so a
+// broker that is down at startup is retried like any other failure.
+func NewKafkaConsumer(brokerAddress string, config ConsumerConfig) (*KafkaConsumer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Consumer.Return.Errors = true
+	saramaConfig.Consumer.Offsets.Initial = config.InitialOffset
+	saramaConfig.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{config.Rebalance}
+	if err := saramaConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid consumer config: %w", err)
+	}
+
+	return newKafkaConsumer(func() (sarama.ConsumerGroup, error) {
+		return sarama.NewConsumerGroup([]string{brokerAddress}, config.GroupID, saramaConfig)
+	}, config), nil
 }
 
-func NewKafkaConsumer(brokerAddress string) (*KafkaConsumer, error) {
-	config := sarama.NewConfig()
-	config.Consumer.Return.Errors = true
+func newKafkaConsumer(connect func() (sarama.ConsumerGroup, error), config ConsumerConfig) *KafkaConsumer {
+	return &KafkaConsumer{
+		connect: connect,
+		config:  config,
+	}
+}
 
-	consumer, err := sarama.NewConsumer([]string{brokerAddress}, config)
+// SetLagObserver sets the observer of the lag of the claimed partitions. It
+// must be called before Run.
+func (consumer *KafkaConsumer) SetLagObserver(observer LagObserver) {
+	consumer.lag = observer
+}
+
+// connected returns the consumer group, creating it if that was not done yet.
+func (consumer *KafkaConsumer) connected() (sarama.ConsumerGroup, error) {
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	if consumer.group != nil {
+		return consumer.group, nil
+	}
+
+	group, err := consumer.connect()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Consumer: %w", err)
 	}
-
-	return &KafkaConsumer{
-		Consumer: consumer,
-	}, nil
+	consumer.group = group
+	consumer.errors.Add(1)
+	go consumer.logErrors(group)
+	return group, nil
 }
 
+// Close leaves the group once the running session was drained.
 func (consumer *KafkaConsumer) Close() error {
-	err := consumer.Consumer.Close()
+	consumer.mu.Lock()
+	group := consumer.group
+	consumer.mu.Unlock()
+	if group == nil {
+		return nil
+	}
+	err := group.Close()
+	consumer.errors.Wait()
 	if err != nil {
 		return fmt.Errorf("failed to close Consumer")
 	}
 	return nil
 }
 
-func (consumer *KafkaConsumer) Consume(ctx context.Context, topic string) error {
-	partitions, err := consumer.Consumer.Partitions(topic)
+// Run consumes topics until ctx is done, restarting after failures or
+// returning the first one depending on the failure policy.
+func (consumer *KafkaConsumer) Run(ctx context.Context, topics []string, handler MessageHandler) error {
+	backoff := consumer.config.RestartBackoff
+	for {
+		err := consumer.Consume(ctx, topics, handler)
+		if err == nil || consumer.config.FailurePolicy == FailureFailFast {
+			return err
+		}
+
+		consumer.setErr(err)
+		logger.Errorf(ctx, "consumer failed, restarting in %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, consumer.config.MaxRestartBackoff)
+	}
+}
+
+// Consume passes the messages of topics to handler until ctx is done. It joins
+// the group again after every rebalance.
+func (consumer *KafkaConsumer) Consume(ctx context.Context, topics []string, handler MessageHandler) error {
+	group, err := consumer.connected()
 	if err != nil {
-		return fmt.Errorf("failed to create Consumer")
+		return err
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(1)
-	for _, partition := range partitions {
-		pc, err := consumer.Consumer.ConsumePartition(topic, partition, sarama.OffsetNewest)
+	groupHandler := &groupHandler{
+		handler:      handler,
+		drainTimeout: consumer.config.DrainTimeout,
+		lag:          consumer.lag,
+		joined:       func() { consumer.setErr(nil) },
+	}
+	for {
+		err = group.Consume(ctx, topics, groupHandler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) || ctx.Err() != nil {
+			return nil
+		}
 		if err != nil {
-			return fmt.Errorf("failed to create Consumer for partition %d: %v", partition, err)
+			return fmt.Errorf("failed to consume topics %v: %w", topics, err)
 		}
-		defer pc.Close()
-		go func(pc sarama.PartitionConsumer) {
-			defer wg.Done()
-			for {
-				select {
-				case message := <-pc.Messages():
-					fmt.Println(string(message.Value))
-				case <-ctx.Done():
-					return
-				}
+	}
+}
+
+// Check returns the error the consumer is restarting after, if any.
+func (consumer *KafkaConsumer) Check(context.Context) error {
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	return consumer.err
+}
+
+func (consumer *KafkaConsumer) setErr(err error) {
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	consumer.err = err
+}
+
+// logErrors logs the errors of the partition consumers until the group is
+// closed.
+func (consumer *KafkaConsumer) logErrors(group sarama.ConsumerGroup) {
+	defer consumer.errors.Done()
+	for err := range group.Errors() {
+		logger.Errorf(context.Background(), "consumer group error: %v", err)
+	}
+}
+
+// groupHandler is the sarama.ConsumerGroupHandler of one group session. Sarama
+// calls ConsumeClaim in a goroutine per partition and ends the session once
+// all of them returned.
+type groupHandler struct {
+	handler      MessageHandler
+	drainTimeout time.Duration
+	lag          LagObserver
+	joined       func()
+}
+
+func (handler *groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	if handler.joined != nil {
+		handler.joined()
+	}
+	logger.Infof(session.Context(), "consumer group member %s joined generation %d with partitions %v",
+		session.MemberID(), session.GenerationID(), session.Claims())
+	return nil
+}
+
+// Cleanup commits the offsets marked in the session before the partitions are
+// handed to another member.
+func (handler *groupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	session.Commit()
+	logger.Infof(session.Context(), "consumer group member %s left generation %d",
+		session.MemberID(), session.GenerationID())
+	return nil
+}
+
+// ConsumeClaim handles the messages of one partition in order. It returns when
+// the partition is revoked by a rebalance or the consumer stops, after the
+// message being handled is done.
+func (handler *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if handler.lag != nil {
+		// the member the partition moves to reports its lag from now on
+		defer handler.lag.ForgetLag(claim.Topic(), claim.Partition())
+	}
+	sessionCtx := session.Context()
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok || sessionCtx.Err() != nil {
+				return nil
+			}
+			ctx, cancel := drainContext(sessionCtx, handler.drainTimeout)
+			err := handler.handler.HandleMessage(ctx, message)
+			if err != nil {
+				logger.Errorf(ctx, "failed to handle message %s/%d/%d: %v",
+					message.Topic, message.Partition, message.Offset, err)
+			}
+			done := ctx.Err() != nil
+			cancel()
+			if done {
+				return nil
+			}
+			session.MarkMessage(message, "")
+			if handler.lag != nil {
+				handler.lag.ObserveLag(message.Topic, message.Partition, claim.HighWaterMarkOffset()-message.Offset-1)
 			}
-		}(pc)
+		case <-sessionCtx.Done():
+			return nil
+		}
+	}
+}
+
+// drainContext keeps the values of parent but is done only timeout after
+// parent is.
+func drainContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(parent, func() {
+		timer := time.AfterFunc(timeout, cancel)
+		context.AfterFunc(ctx, func() { timer.Stop() })
+	})
+	return ctx, func() {
+		stop()
+		cancel()
 	}
-	wg.Wait()
-	return err
 }
@@ -0,0 +1,264 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSession records the messages marked by the handler.
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	marked []int64
+}
+
+func (session *fakeSession) Context() context.Context {
+	return session.ctx
+}
+
+func (session *fakeSession) MarkMessage(message *sarama.ConsumerMessage, _ string) {
+	session.marked = append(session.marked, message.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	topic         string
+	partition     int32
+	messages      chan *sarama.ConsumerMessage
+	highWaterMark int64
+}
+
+func (claim *fakeClaim) Topic() string {
+	return claim.topic
+}
+
+func (claim *fakeClaim) Partition() int32 {
+	return claim.partition
+}
+
+func (claim *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return claim.messages
+}
+
+func (claim *fakeClaim) HighWaterMarkOffset() int64 {
+	return claim.highWaterMark
+}
+
+func TestGroupHandler_ConsumeClaim(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	session := &fakeSession{ctx: context.Background()}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 3)}
+	for offset := int64(0); offset < 3; offset++ {
+		claim.messages <- &sarama.ConsumerMessage{Topic: "crud", Offset: offset}
+	}
+	close(claim.messages)
+	var handled []int64
+	handler := &groupHandler{handler: MessageHandlerFunc(func(_ context.Context, message *sarama.ConsumerMessage) error {
+		handled = append(handled, message.Offset)
+		if message.Offset == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	})}
+
+	// act
+	err := handler.ConsumeClaim(session, claim)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2}, handled)
+	assert.Equal(t, []int64{0, 1, 2}, session.marked)
+}
+
+func TestGroupHandler_ConsumeClaim_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	session := &fakeSession{ctx: ctx}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage)}
+	handler := &groupHandler{handler: NewRegistry(DefaultRetryPolicy)}
+
+	// act
+	err := handler.ConsumeClaim(session, claim)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, session.marked)
+}
+
+// lagRecorder records the observed lags and the forgotten partitions.
+type lagRecorder struct {
+	lags      []int64
+	forgotten []int32
+}
+
+func (recorder *lagRecorder) ObserveLag(_ string, _ int32, lag int64) {
+	recorder.lags = append(recorder.lags, lag)
+}
+
+func (recorder *lagRecorder) ForgetLag(_ string, partition int32) {
+	recorder.forgotten = append(recorder.forgotten, partition)
+}
+
+func TestGroupHandler_ConsumeClaim_Lag(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	session := &fakeSession{ctx: context.Background()}
+	claim := &fakeClaim{topic: "crud", partition: 2, messages: make(chan *sarama.ConsumerMessage, 2), highWaterMark: 10}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "crud", Partition: 2, Offset: 7}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "crud", Partition: 2, Offset: 8}
+	close(claim.messages)
+	lag := &lagRecorder{}
+	handler := &groupHandler{handler: MessageHandlerFunc(func(context.Context, *sarama.ConsumerMessage) error {
+		return nil
+	}), lag: lag}
+
+	// act
+	err := handler.ConsumeClaim(session, claim)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, lag.lags)
+	assert.Equal(t, []int32{2}, lag.forgotten)
+}
+
+func TestParseInitialOffset(t *testing.T) {
+	t.Parallel()
+
+	offset, err := ParseInitialOffset("oldest")
+	assert.NoError(t, err)
+	assert.Equal(t, sarama.OffsetOldest, offset)
+
+	_, err = ParseInitialOffset("latest")
+	assert.Error(t, err)
+}
+
+// fakeGroup fails the first failures calls of Consume and then blocks until
+// the context is done.
+type fakeGroup struct {
+	sarama.ConsumerGroup
+	failures int
+	calls    int
+	errors   chan error
+}
+
+func (group *fakeGroup) Consume(ctx context.Context, _ []string, _ sarama.ConsumerGroupHandler) error {
+	group.calls++
+	if group.calls <= group.failures {
+		return errors.New("coordinator not available")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (group *fakeGroup) Errors() <-chan error {
+	return group.errors
+}
+
+func (group *fakeGroup) Close() error {
+	close(group.errors)
+	return nil
+}
+
+func TestKafkaConsumer_Run(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		policy        FailurePolicy
+		expectedCalls int
+		expectedError bool
+	}{{
+		name:          "restart",
+		policy:        FailureRestart,
+		expectedCalls: 3,
+	}, {
+		name:          "fail fast",
+		policy:        FailureFailFast,
+		expectedError: true,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// arrange
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			config := DefaultConsumerConfig("group")
+			config.FailurePolicy = tc.policy
+			config.RestartBackoff = time.Millisecond
+			group := &fakeGroup{failures: 2, errors: make(chan error)}
+			connected := false
+			consumer := newKafkaConsumer(func() (sarama.ConsumerGroup, error) {
+				// The broker is down when the consumer starts.
+				if !connected {
+					connected = true
+					return nil, errors.New("broker unavailable")
+				}
+				return group, nil
+			}, config)
+
+			// act
+			err := consumer.Run(ctx, []string{"crud"}, NewRegistry(DefaultRetryPolicy))
+
+			// assert
+			assert.Equal(t, tc.expectedError, err != nil)
+			assert.Equal(t, tc.expectedCalls, group.calls)
+			// The restarted consumer never joined the group again.
+			assert.Equal(t, !tc.expectedError, consumer.Check(ctx) != nil)
+			assert.NoError(t, consumer.Close())
+		})
+	}
+}
+
+func TestGroupHandler_ConsumeClaim_Drain(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	sessionCtx, stop := context.WithCancel(context.Background())
+	session := &fakeSession{ctx: sessionCtx}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+	claim.messages <- &sarama.ConsumerMessage{Offset: 0}
+	claim.messages <- &sarama.ConsumerMessage{Offset: 1}
+	var handled []int64
+	handler := &groupHandler{drainTimeout: time.Minute, handler: MessageHandlerFunc(func(ctx context.Context, message *sarama.ConsumerMessage) error {
+		// The consumer stops while the first message is being handled.
+		stop()
+		handled = append(handled, message.Offset)
+		return ctx.Err()
+	})}
+
+	// act
+	err := handler.ConsumeClaim(session, claim)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0}, handled)
+	assert.Equal(t, []int64{0}, session.marked)
+}
+
+func TestDrainContext(t *testing.T) {
+	t.Parallel()
+
+	parent, stop := context.WithCancel(context.Background())
+	ctx, cancel := drainContext(parent, 10*time.Millisecond)
+	defer cancel()
+
+	stop()
+	assert.NoError(t, ctx.Err())
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,248 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+)
+
+// Headers added to messages sent to a retry or dead-letter topic. The original
+// headers are kept from the first failure on.
+const (
+	HeaderFailureReason     = "failure-reason"
+	HeaderAttempt           = "attempt"
+	HeaderRetryAt           = "retry-at"
+	HeaderOriginalTopic     = "original-topic"
+	HeaderOriginalPartition = "original-partition"
+	HeaderOriginalOffset    = "original-offset"
+	// HeaderFailedHandlers lists the Registry handlers that failed on the
+	// message, separated by commas. It is kept when the message is replayed,
+	// so only those handlers get it again.
+	HeaderFailedHandlers = "failed-handlers"
+)
+
+var failureHeaders = map[string]bool{
+	HeaderFailureReason:     true,
+	HeaderAttempt:           true,
+	HeaderRetryAt:           true,
+	HeaderOriginalTopic:     true,
+	HeaderOriginalPartition: true,
+	HeaderOriginalOffset:    true,
+}
+
+const republishBackoff = time.Second
+
+// MessagePublisher sends a message and waits for Kafka to accept it.
+type MessagePublisher interface {
+	SendMessage(message *sarama.ProducerMessage) error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (err permanentError) Error() string {
+	return err.err.Error()
+}
+
+func (err permanentError) Unwrap() error {
+	return err.err
+}
+
+// Permanent marks err as one retrying cannot fix, so the message goes to the
+// dead-letter topic right away.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked by Permanent.
+func IsPermanent(err error) bool {
+	return errors.As(err, &permanentError{})
+}
+
+// DeadLetterConfig configures the retry and dead-letter topics of a topic.
+type DeadLetterConfig struct {
+	// RetryDelays are the delay tiers. A message failing for the n-th time is
+	// sent to the n-th retry topic and processed again after the n-th delay.
+	RetryDelays []time.Duration
+}
+
+// DefaultDeadLetterConfig retries a message after a minute and then after ten.
+func DefaultDeadLetterConfig() DeadLetterConfig {
+	return DeadLetterConfig{RetryDelays: []time.Duration{time.Minute, 10 * time.Minute}}
+}
+
+// RetryTopic is the topic of the retry tier tier, starting at 1.
+func RetryTopic(topic string, tier int) string {
+	return fmt.Sprintf("%s-retry-%d", topic, tier)
+}
+
+// DeadLetterTopic is the topic of the messages of topic that failed every
+// retry.
+func DeadLetterTopic(topic string) string {
+	return topic + "-dlq"
+}
+
+// DeadLetterHandler is a MessageHandler that sends the messages handler fails
+// on to the next retry topic, and to the dead-letter topic after the last one.
+type DeadLetterHandler struct {
+	handler   MessageHandler
+	publisher MessagePublisher
+	topic     string
+	delays    []time.Duration
+	tiers     map[string]int
+	now       func() time.Time
+}
+
+func NewDeadLetterHandler(handler MessageHandler, publisher MessagePublisher, topic string, config DeadLetterConfig) *DeadLetterHandler {
+	tiers := make(map[string]int, len(config.RetryDelays))
+	for i := range config.RetryDelays {
+		tiers[RetryTopic(topic, i+1)] = i + 1
+	}
+	return &DeadLetterHandler{
+		handler:   handler,
+		publisher: publisher,
+		topic:     topic,
+		delays:    config.RetryDelays,
+		tiers:     tiers,
+		now:       time.Now,
+	}
+}
+
+// Topics are the main topic and the retry topics, which must all be consumed.
+func (handler *DeadLetterHandler) Topics() []string {
+	topics := []string{handler.topic}
+	for i := range handler.delays {
+		topics = append(topics, RetryTopic(handler.topic, i+1))
+	}
+	return topics
+}
+
+// HandleMessage waits until a message from a retry topic is due and passes it
+// to the handler. It returns an error only when ctx is done before the message
+// was handled or sent on, in which case it must not be committed.
+func (handler *DeadLetterHandler) HandleMessage(ctx context.Context, message *sarama.ConsumerMessage) error {
+	if retryAt, ok := headerValue(message.Headers, HeaderRetryAt); ok {
+		at, err := time.Parse(time.RFC3339Nano, retryAt)
+		if err == nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(at.Sub(handler.now())):
+			}
+		}
+	}
+
+	err := handler.handler.HandleMessage(ctx, message)
+	if err == nil || ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	next := handler.next(message, err)
+	logger.Errorf(ctx, "failed to handle message %s/%d/%d, sending it to %s: %v",
+		message.Topic, message.Partition, message.Offset, next.Topic, err)
+	for {
+		if err = handler.publisher.SendMessage(next); err == nil {
+			return nil
+		}
+		logger.Errorf(ctx, "failed to send message to %s: %v", next.Topic, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(republishBackoff):
+		}
+	}
+}
+
+// next builds the message sent to the retry or dead-letter topic after message
+// failed with err.
+func (handler *DeadLetterHandler) next(message *sarama.ConsumerMessage, err error) *sarama.ProducerMessage {
+	tier := handler.tiers[message.Topic]
+	attempt := tier + 1
+	topic := DeadLetterTopic(handler.topic)
+	var retryAt string
+	if tier < len(handler.delays) && !IsPermanent(err) {
+		topic = RetryTopic(handler.topic, tier+1)
+		retryAt = handler.now().Add(handler.delays[tier]).UTC().Format(time.RFC3339Nano)
+	}
+
+	originalTopic, ok := headerValue(message.Headers, HeaderOriginalTopic)
+	originalPartition, _ := headerValue(message.Headers, HeaderOriginalPartition)
+	originalOffset, _ := headerValue(message.Headers, HeaderOriginalOffset)
+	if !ok {
+		originalTopic = message.Topic
+		originalPartition = strconv.FormatInt(int64(message.Partition), 10)
+		originalOffset = strconv.FormatInt(message.Offset, 10)
+	}
+
+	headers := withoutFailureHeaders(message.Headers)
+	var failed *handlersError
+	if errors.As(err, &failed) {
+		kept := headers[:0]
+		for _, header := range headers {
+			if string(header.Key) != HeaderFailedHandlers {
+				kept = append(kept, header)
+			}
+		}
+		headers = append(kept, sarama.RecordHeader{
+			Key: []byte(HeaderFailedHandlers), Value: []byte(strings.Join(failed.handlers, ",")),
+		})
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderFailureReason), Value: []byte(err.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderAttempt), Value: []byte(strconv.Itoa(attempt))},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(originalTopic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(originalPartition)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(originalOffset)},
+	)
+	if retryAt != "" {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderRetryAt), Value: []byte(retryAt)})
+	}
+	return &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+}
+
+// ReplayMessage builds the message that sends a message of the dead-letter
+// topic back to its original topic, without the failure headers.
+func ReplayMessage(message *sarama.ConsumerMessage) (*sarama.ProducerMessage, error) {
+	topic, ok := headerValue(message.Headers, HeaderOriginalTopic)
+	if !ok {
+		return nil, fmt.Errorf("message %s/%d/%d has no %s header",
+			message.Topic, message.Partition, message.Offset, HeaderOriginalTopic)
+	}
+	return &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(message.Key),
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: withoutFailureHeaders(message.Headers),
+	}, nil
+}
+
+func headerValue(headers []*sarama.RecordHeader, key string) (string, bool) {
+	for _, header := range headers {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value), true
+		}
+	}
+	return "", false
+}
+
+func withoutFailureHeaders(headers []*sarama.RecordHeader) []sarama.RecordHeader {
+	var kept []sarama.RecordHeader
+	for _, header := range headers {
+		if header != nil && !failureHeaders[string(header.Key)] {
+			kept = append(kept, *header)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,175 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePublisher struct {
+	sent []*sarama.ProducerMessage
+}
+
+func (publisher *fakePublisher) SendMessage(message *sarama.ProducerMessage) error {
+	publisher.sent = append(publisher.sent, message)
+	return nil
+}
+
+func headers(message *sarama.ProducerMessage) map[string]string {
+	values := make(map[string]string)
+	for _, header := range message.Headers {
+		values[string(header.Key)] = string(header.Value)
+	}
+	return values
+}
+
+func consumed(message *sarama.ProducerMessage, partition int32, offset int64) *sarama.ConsumerMessage {
+	consumed := &sarama.ConsumerMessage{Topic: message.Topic, Partition: partition, Offset: offset, Key: []byte("10"), Value: []byte("event")}
+	for i := range message.Headers {
+		consumed.Headers = append(consumed.Headers, &message.Headers[i])
+	}
+	return consumed
+}
+
+func TestDeadLetterHandler_HandleMessage(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	publisher := &fakePublisher{}
+	failing := MessageHandlerFunc(func(context.Context, *sarama.ConsumerMessage) error {
+		return errors.New("search index unavailable")
+	})
+	handler := NewDeadLetterHandler(failing, publisher, "crud", DeadLetterConfig{RetryDelays: []time.Duration{time.Minute}})
+	handler.now = func() time.Time { return now }
+	message := &sarama.ConsumerMessage{Topic: "crud", Partition: 2, Offset: 7, Key: []byte("10"), Value: []byte("event"),
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderContentType), Value: []byte(contentTypeProtobuf)}}}
+
+	// act
+	errFirst := handler.HandleMessage(context.Background(), message)
+	require.Len(t, publisher.sent, 1)
+	now = now.Add(time.Minute)
+	errRetry := handler.HandleMessage(context.Background(), consumed(publisher.sent[0], 0, 3))
+
+	// assert
+	assert.NoError(t, errFirst)
+	assert.NoError(t, errRetry)
+	assert.Equal(t, []string{"crud", "crud-retry-1"}, handler.Topics())
+	require.Len(t, publisher.sent, 2)
+	assert.Equal(t, "crud-retry-1", publisher.sent[0].Topic)
+	assert.Equal(t, map[string]string{
+		HeaderContentType:       contentTypeProtobuf,
+		HeaderFailureReason:     "search index unavailable",
+		HeaderAttempt:           "1",
+		HeaderOriginalTopic:     "crud",
+		HeaderOriginalPartition: "2",
+		HeaderOriginalOffset:    "7",
+		HeaderRetryAt:           "2026-10-17T12:01:00Z",
+	}, headers(publisher.sent[0]))
+	assert.Equal(t, "crud-dlq", publisher.sent[1].Topic)
+	assert.Equal(t, map[string]string{
+		HeaderContentType:       contentTypeProtobuf,
+		HeaderFailureReason:     "search index unavailable",
+		HeaderAttempt:           "2",
+		HeaderOriginalTopic:     "crud",
+		HeaderOriginalPartition: "2",
+		HeaderOriginalOffset:    "7",
+	}, headers(publisher.sent[1]))
+
+	replayed, err := ReplayMessage(consumed(publisher.sent[1], 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "crud", replayed.Topic)
+	assert.Equal(t, map[string]string{HeaderContentType: contentTypeProtobuf}, headers(replayed))
+}
+
+func TestDeadLetterHandler_HandleMessage_FailedHandlers(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	publisher := &fakePublisher{}
+	registry := NewRegistry(RetryPolicy{MaxAttempts: 1})
+	audited, indexed := 0, 0
+	require.NoError(t, registry.Register("audit", EventHandlerFunc(func(context.Context, *events.ArticleEvent) error {
+		audited++
+		return nil
+	})))
+	require.NoError(t, registry.Register("search", EventHandlerFunc(func(context.Context, *events.ArticleEvent) error {
+		indexed++
+		if indexed == 1 {
+			return errors.New("search index unavailable")
+		}
+		return nil
+	})))
+	handler := NewDeadLetterHandler(registry, publisher, "crud", DeadLetterConfig{RetryDelays: []time.Duration{time.Minute}})
+	handler.now = func() time.Time { return now }
+	message := eventMessage(t, events.EventType_EVENT_TYPE_ARTICLE_CREATED)
+	message.Topic = "crud"
+
+	// act
+	errFirst := handler.HandleMessage(context.Background(), message)
+	require.Len(t, publisher.sent, 1)
+	now = now.Add(time.Minute)
+	retry := consumed(publisher.sent[0], 0, 3)
+	retry.Value = message.Value
+	errRetry := handler.HandleMessage(context.Background(), retry)
+
+	// assert
+	assert.NoError(t, errFirst)
+	assert.NoError(t, errRetry)
+	assert.Len(t, publisher.sent, 1)
+	assert.Equal(t, "search", headers(publisher.sent[0])[HeaderFailedHandlers])
+	assert.Equal(t, 1, audited, "the handler that succeeded does not get the retry")
+	assert.Equal(t, 2, indexed)
+
+	replayed, err := ReplayMessage(consumed(publisher.sent[0], 0, 3))
+	require.NoError(t, err)
+	assert.Equal(t, "search", headers(replayed)[HeaderFailedHandlers])
+}
+
+func TestDeadLetterHandler_HandleMessage_Permanent(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	publisher := &fakePublisher{}
+	handler := NewDeadLetterHandler(NewRegistry(DefaultRetryPolicy), publisher, "crud", DefaultDeadLetterConfig())
+
+	// act
+	err := handler.HandleMessage(context.Background(), &sarama.ConsumerMessage{Topic: "crud", Value: []byte("not protobuf")})
+
+	// assert
+	assert.NoError(t, err)
+	require.Len(t, publisher.sent, 1)
+	assert.Equal(t, "crud-dlq", publisher.sent[0].Topic)
+}
+
+func TestDeadLetterHandler_HandleMessage_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	publisher := &fakePublisher{}
+	handled := false
+	handler := NewDeadLetterHandler(MessageHandlerFunc(func(context.Context, *sarama.ConsumerMessage) error {
+		handled = true
+		return nil
+	}), publisher, "crud", DefaultDeadLetterConfig())
+	message := &sarama.ConsumerMessage{Topic: "crud-retry-1", Headers: []*sarama.RecordHeader{
+		{Key: []byte(HeaderRetryAt), Value: []byte(time.Now().Add(time.Hour).Format(time.RFC3339Nano))},
+	}}
+
+	// act
+	err := handler.HandleMessage(ctx, message)
+
+	// assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, handled)
+	assert.Empty(t, publisher.sent)
+}
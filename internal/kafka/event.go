@@ -0,0 +1,195 @@
+package kafka
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SchemaVersion is the version of events.ArticleEvent written by this service.
+const SchemaVersion = events.SchemaVersion_SCHEMA_VERSION_1
+
+// Headers of every published message.
+const (
+	HeaderContentType   = "content-type"
+	HeaderSchemaVersion = "schema-version"
+	HeaderEventType     = "event-type"
+	HeaderEventID       = "event-id"
+)
+
+// Encoding is the wire format of published events.
+type Encoding int
+
+const (
+	EncodingProtobuf Encoding = iota
+	EncodingJSON
+)
+
+const (
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+var contentTypes = map[Encoding]string{
+	EncodingProtobuf: contentTypeProtobuf,
+	EncodingJSON:     contentTypeJSON,
+}
+
+// ParseEncoding parses "protobuf" or "json".
+func ParseEncoding(value string) (Encoding, error) {
+	switch value {
+	case "protobuf":
+		return EncodingProtobuf, nil
+	case "json":
+		return EncodingJSON, nil
+	}
+	return 0, fmt.Errorf("unknown event encoding %q", value)
+}
+
+// Event is an article event. It is published as an events.ArticleEvent.
+type Event struct {
+	// ID is generated by the producer when empty.
+	ID            string
+	Type          events.EventType
+	AggregateID   int64
+	Before        *repository.Article
+	After         *repository.Article
+	ChangedFields []string
+	Time          time.Time
+}
+
+var outboxEventTypes = map[string]events.EventType{
+	repository.EventArticleCreated:  events.EventType_EVENT_TYPE_ARTICLE_CREATED,
+	repository.EventArticleUpdated:  events.EventType_EVENT_TYPE_ARTICLE_UPDATED,
+	repository.EventArticleDeleted:  events.EventType_EVENT_TYPE_ARTICLE_DELETED,
+	repository.EventArticleRestored: events.EventType_EVENT_TYPE_ARTICLE_RESTORED,
+	repository.EventArticlePurged:   events.EventType_EVENT_TYPE_ARTICLE_PURGED,
+}
+
+// outboxPayload is the layout of repository.OutboxRecord.Payload.
+type outboxPayload struct {
+	repository.Article
+	Before        *repository.Article `json:"before"`
+	ChangedFields []string            `json:"changed_fields"`
+}
+
+// EventFromOutbox converts an outbox record into an event. The event id is
+// derived from the record, so a record published twice keeps its id.
+func EventFromOutbox(record repository.OutboxRecord) (Event, error) {
+	eventType, ok := outboxEventTypes[record.EventType]
+	if !ok {
+		return Event{}, fmt.Errorf("unknown outbox event type %q", record.EventType)
+	}
+	var payload outboxPayload
+	if err := json.Unmarshal([]byte(record.Payload), &payload); err != nil {
+		return Event{}, fmt.Errorf("failed to decode outbox event %d: %w", record.ID, err)
+	}
+
+	event := Event{
+		ID:            "outbox-" + strconv.FormatInt(record.ID, 10),
+		Type:          eventType,
+		AggregateID:   record.AggregateID,
+		Before:        payload.Before,
+		After:         &payload.Article,
+		ChangedFields: payload.ChangedFields,
+		Time:          record.CreatedAt,
+	}
+	if eventType == events.EventType_EVENT_TYPE_ARTICLE_PURGED {
+		event.Before, event.After = event.After, nil
+	}
+	return event, nil
+}
+
+func newEventID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func articleState(article *repository.Article) *events.ArticleState {
+	if article == nil {
+		return nil
+	}
+	state := &events.ArticleState{
+		Id:        article.ID,
+		Name:      article.Name,
+		Rating:    article.Rating,
+		CreatedAt: timestamppb.New(article.CreatedAt),
+		Version:   article.Version,
+	}
+	if article.DeletedAt != nil {
+		state.DeletedAt = timestamppb.New(*article.DeletedAt)
+	}
+	return state
+}
+
+// encodeEvent builds the value and headers of the message of event.
+func encodeEvent(event Event, encoding Encoding) ([]byte, []sarama.RecordHeader, error) {
+	contentType, ok := contentTypes[encoding]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown event encoding %d", encoding)
+	}
+	message := &events.ArticleEvent{
+		EventId:       event.ID,
+		SchemaVersion: SchemaVersion,
+		AggregateId:   event.AggregateID,
+		Type:          event.Type,
+		Before:        articleState(event.Before),
+		After:         articleState(event.After),
+		ChangedFields: event.ChangedFields,
+		Time:          timestamppb.New(event.Time),
+	}
+
+	var value []byte
+	var err error
+	if encoding == EncodingJSON {
+		value, err = protojson.Marshal(message)
+	} else {
+		value, err = proto.Marshal(message)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, []sarama.RecordHeader{
+		{Key: []byte(HeaderContentType), Value: []byte(contentType)},
+		{Key: []byte(HeaderSchemaVersion), Value: []byte(strconv.Itoa(int(SchemaVersion)))},
+		{Key: []byte(HeaderEventType), Value: []byte(event.Type.String())},
+		{Key: []byte(HeaderEventID), Value: []byte(event.ID)},
+	}, nil
+}
+
+// DecodeEvent parses a message published by KafkaProducer. Messages without a
+// content type are taken to be protobuf.
+func DecodeEvent(message *sarama.ConsumerMessage) (*events.ArticleEvent, error) {
+	contentType, ok := headerValue(message.Headers, HeaderContentType)
+	if !ok {
+		contentType = contentTypeProtobuf
+	}
+
+	event := &events.ArticleEvent{}
+	var err error
+	switch contentType {
+	case contentTypeProtobuf:
+		err = proto.Unmarshal(message.Value, event)
+	case contentTypeJSON:
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(message.Value, event)
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	return event, nil
+}
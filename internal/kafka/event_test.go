@@ -0,0 +1,122 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFromOutbox(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		record        repository.OutboxRecord
+		expectedEvent Event
+		expectedError bool
+	}{{
+		name: "update",
+		record: repository.OutboxRecord{ID: 5, AggregateID: 10, EventType: repository.EventArticleUpdated, CreatedAt: createdAt,
+			Payload: `{"id":10,"name":"new","rating":2,"version":2,"before":{"id":10,"name":"old","rating":2,"version":1},"changed_fields":["name"]}`},
+		expectedEvent: Event{
+			ID:            "outbox-5",
+			Type:          events.EventType_EVENT_TYPE_ARTICLE_UPDATED,
+			AggregateID:   10,
+			Before:        &repository.Article{ID: 10, Name: "old", Rating: 2, Version: 1},
+			After:         &repository.Article{ID: 10, Name: "new", Rating: 2, Version: 2},
+			ChangedFields: []string{"name"},
+			Time:          createdAt,
+		},
+	}, {
+		name: "purge",
+		record: repository.OutboxRecord{ID: 6, AggregateID: 10, EventType: repository.EventArticlePurged, CreatedAt: createdAt,
+			Payload: `{"id":10,"name":"old","rating":2,"version":3}`},
+		expectedEvent: Event{
+			ID:          "outbox-6",
+			Type:        events.EventType_EVENT_TYPE_ARTICLE_PURGED,
+			AggregateID: 10,
+			Before:      &repository.Article{ID: 10, Name: "old", Rating: 2, Version: 3},
+			Time:        createdAt,
+		},
+	}, {
+		name:          "unknown type",
+		record:        repository.OutboxRecord{ID: 7, EventType: "ArticleRenamed", Payload: `{}`},
+		expectedError: true,
+	}, {
+		name:          "malformed payload",
+		record:        repository.OutboxRecord{ID: 8, EventType: repository.EventArticleCreated, Payload: `{`},
+		expectedError: true,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := EventFromOutbox(tc.record)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEvent, event)
+		})
+	}
+}
+
+func TestEncodeEvent(t *testing.T) {
+	t.Parallel()
+
+	deletedAt := time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC)
+	event := Event{
+		ID:          "outbox-1",
+		Type:        events.EventType_EVENT_TYPE_ARTICLE_DELETED,
+		AggregateID: 10,
+		Before:      &repository.Article{ID: 10, Name: "name", Rating: 2, Version: 1},
+		After:       &repository.Article{ID: 10, Name: "name", Rating: 2, Version: 2, DeletedAt: &deletedAt},
+		Time:        deletedAt,
+	}
+
+	for _, encoding := range []Encoding{EncodingProtobuf, EncodingJSON} {
+		encoding := encoding
+		t.Run(contentTypes[encoding], func(t *testing.T) {
+			t.Parallel()
+
+			value, headers, err := encodeEvent(event, encoding)
+			require.NoError(t, err)
+			message := &sarama.ConsumerMessage{Value: value}
+			for i := range headers {
+				message.Headers = append(message.Headers, &headers[i])
+			}
+			decoded, err := DecodeEvent(message)
+
+			require.NoError(t, err)
+			assert.Equal(t, sarama.RecordHeader{Key: []byte(HeaderContentType), Value: []byte(contentTypes[encoding])}, headers[0])
+			assert.Equal(t, "outbox-1", decoded.EventId)
+			assert.Equal(t, SchemaVersion, decoded.SchemaVersion)
+			assert.Equal(t, events.EventType_EVENT_TYPE_ARTICLE_DELETED, decoded.Type)
+			assert.Equal(t, int64(1), decoded.Before.Version)
+			assert.Nil(t, decoded.Before.DeletedAt)
+			assert.Equal(t, deletedAt, decoded.After.DeletedAt.AsTime())
+		})
+	}
+}
+
+func TestDecodeEvent_UnsupportedContentType(t *testing.T) {
+	t.Parallel()
+
+	_, err := DecodeEvent(&sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{{Key: []byte(HeaderContentType), Value: []byte("text/plain")}},
+		Value:   []byte("EventType: x"),
+	})
+
+	assert.Error(t, err)
+}
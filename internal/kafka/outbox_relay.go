@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+)
+
+const (
+	defaultRelayInterval  = time.Second
+	defaultRelayBatchSize = 100
+	outboxRetention       = 7 * 24 * time.Hour
+	outboxPurgeInterval   = time.Hour
+)
+
+// OutboxStore is the storage side of the outbox. Relay must hand the events of
+// one article to publish in the order they were written and only after the
+// earlier ones were published.
+type OutboxStore interface {
+	Relay(ctx context.Context, limit int, publish func(record repository.OutboxRecord) error) (int, error)
+	PurgeDelivered(ctx context.Context, before time.Time) (int64, error)
+}
+
+// OutboxRelay publishes events written to the outbox by the repository.
+// Delivery is at least once: an event published right before the relay
+// crashes is published again once its lease expired.
+type OutboxRelay struct {
+	store     OutboxStore
+	producer  KafkaInterface
+	topic     string
+	interval  time.Duration
+	batchSize int
+}
+
+func NewOutboxRelay(store OutboxStore, producer KafkaInterface, topic string) *OutboxRelay {
+	return &OutboxRelay{
+		store:     store,
+		producer:  producer,
+		topic:     topic,
+		interval:  defaultRelayInterval,
+		batchSize: defaultRelayBatchSize,
+	}
+}
+
+// Run polls the outbox until ctx is done. While events keep coming it polls
+// again right away, otherwise it waits for the next tick.
+func (relay *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+	var lastPurge time.Time
+
+	for {
+		published, err := relay.store.Relay(ctx, relay.batchSize, relay.publish)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf(ctx, "failed to relay outbox events: %v", err)
+		}
+
+		if now := time.Now(); now.Sub(lastPurge) >= outboxPurgeInterval {
+			lastPurge = now
+			if _, err = relay.store.PurgeDelivered(ctx, now.Add(-outboxRetention)); err != nil && ctx.Err() == nil {
+				logger.Errorf(ctx, "failed to purge delivered outbox events: %v", err)
+			}
+		}
+
+		if published > 0 && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (relay *OutboxRelay) publish(record repository.OutboxRecord) error {
+	event, err := EventFromOutbox(record)
+	if err != nil {
+		return err
+	}
+	return relay.producer.SendEvent(relay.topic, event)
+}
@@ -0,0 +1,87 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/kafka"
+	mock_kafka_interface "github.com/NRKA/gRPC-Server/internal/kafka/mocks"
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeOutbox hands its records to the relay once and then stops the relay.
+type fakeOutbox struct {
+	records   []repository.OutboxRecord
+	delivered []int64
+	failed    map[int64]error
+	stop      context.CancelFunc
+}
+
+func (store *fakeOutbox) Relay(_ context.Context, limit int, publish func(record repository.OutboxRecord) error) (int, error) {
+	defer store.stop()
+	published := 0
+	for _, record := range store.records[:min(limit, len(store.records))] {
+		if err := publish(record); err != nil {
+			store.failed[record.ID] = err
+			continue
+		}
+		store.delivered = append(store.delivered, record.ID)
+		published++
+	}
+	store.records = nil
+	return published, nil
+}
+
+func (store *fakeOutbox) PurgeDelivered(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	store := &fakeOutbox{
+		records: []repository.OutboxRecord{
+			{ID: 1, AggregateID: 10, EventType: repository.EventArticleCreated, Payload: `{"id":10}`, CreatedAt: createdAt},
+			{ID: 2, AggregateID: 20, EventType: repository.EventArticleDeleted, Payload: `{"id":20}`, CreatedAt: createdAt},
+		},
+		failed: make(map[int64]error),
+		stop:   cancel,
+	}
+	errBrokerDown := errors.New("broker is down")
+	mockProducer := mock_kafka_interface.NewMockKafkaInterface(ctrl)
+	mockProducer.EXPECT().SendEvent("articles", kafka.Event{
+		ID:          "outbox-1",
+		Type:        events.EventType_EVENT_TYPE_ARTICLE_CREATED,
+		AggregateID: 10,
+		After:       &repository.Article{ID: 10},
+		Time:        createdAt,
+	}).Return(nil)
+	mockProducer.EXPECT().SendEvent("articles", kafka.Event{
+		ID:          "outbox-2",
+		Type:        events.EventType_EVENT_TYPE_ARTICLE_DELETED,
+		AggregateID: 20,
+		After:       &repository.Article{ID: 20},
+		Time:        createdAt,
+	}).Return(errBrokerDown)
+
+	relay := kafka.NewOutboxRelay(store, mockProducer, "articles")
+
+	// act
+	relay.Run(ctx)
+
+	// assert
+	assert.Equal(t, []int64{1}, store.delivered)
+	assert.Equal(t, map[int64]error{2: errBrokerDown}, store.failed)
+}
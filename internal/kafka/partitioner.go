@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// Partitioner chooses the partition of a message from its key, the article id.
+// All of them send the events of one article to the same partition, so they
+// keep their order.
+type Partitioner int
+
+const (
+	// PartitionerHash is sarama's FNV-1a hash of the key.
+	PartitionerHash Partitioner = iota
+	// PartitionerReference is the murmur2 hash of the Java client, for topics
+	// shared with producers written against it.
+	PartitionerReference
+	// PartitionerCRC32 is the CRC32 hash of librdkafka's consistent partitioner.
+	PartitionerCRC32
+)
+
+var partitioners = map[Partitioner]sarama.PartitionerConstructor{
+	PartitionerHash:      sarama.NewHashPartitioner,
+	PartitionerReference: sarama.NewReferenceHashPartitioner,
+	PartitionerCRC32:     sarama.NewConsistentCRCHashPartitioner,
+}
+
+// ParsePartitioner parses "hash", "reference" or "crc32".
+func ParsePartitioner(value string) (Partitioner, error) {
+	switch value {
+	case "hash":
+		return PartitionerHash, nil
+	case "reference":
+		return PartitionerReference, nil
+	case "crc32":
+		return PartitionerCRC32, nil
+	}
+	return 0, fmt.Errorf("unknown partitioner %q", value)
+}
+
+func (partitioner Partitioner) constructor() (sarama.PartitionerConstructor, error) {
+	constructor, ok := partitioners[partitioner]
+	if !ok {
+		return nil, fmt.Errorf("unknown partitioner %d", partitioner)
+	}
+	return constructor, nil
+}
@@ -1,55 +1,207 @@
 package kafka
 
 import (
+	"context"
 	"fmt"
 	"github.com/IBM/sarama"
-	"time"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"strconv"
+	"sync"
 )
 
-type Event struct {
-	TimeStamp   time.Time
-	Type        string
-	RequestBody string
-}
-
 type KafkaProducer struct {
+	brokerAddress string
+	config        *sarama.Config
+
+	// connecting serializes the connection attempts, which are made without
+	// holding mu, so sends on a connected producer never wait for a dial.
+	connecting sync.Mutex
+
+	mu       sync.Mutex
+	client   sarama.Client
 	producer sarama.SyncProducer
+	encoding Encoding
+	// checked is closed once the running check, if any, is over, and checkErr
+	// is its outcome.
+	checked  chan struct{}
+	checkErr error
 }
 
-func NewKafkaProducer(brokerAddress string) (*KafkaProducer, error) {
-	config := sarama.NewConfig()
-	config.Producer.Return.Successes = true
+// NewKafkaProducer creates a producer that keys messages by article id and
+// spreads them over the partitions of the topic with partitioner. When the
+// broker cannot be reached it connects on the first send instead.
+func NewKafkaProducer(brokerAddress string, partitioner Partitioner) (*KafkaProducer, error) {
+	config, err := newProducerConfig(partitioner)
+	if err != nil {
+		return nil, err
+	}
+
+	producer := &KafkaProducer{
+		brokerAddress: brokerAddress,
+		config:        config,
+	}
+	if _, err = producer.connected(); err != nil {
+		logger.Errorf(context.Background(), "Kafka is unavailable, connecting on the first event: %v", err)
+	}
+	return producer, nil
+}
+
+// connected returns the sarama producer, connecting to the broker if that was
+// not done yet.
+func (producer *KafkaProducer) connected() (sarama.SyncProducer, error) {
+	if syncProducer, _ := producer.current(); syncProducer != nil {
+		return syncProducer, nil
+	}
+	producer.connecting.Lock()
+	defer producer.connecting.Unlock()
+	if syncProducer, _ := producer.current(); syncProducer != nil {
+		return syncProducer, nil
+	}
 
-	producer, err := sarama.NewSyncProducer([]string{brokerAddress}, config)
+	client, err := sarama.NewClient([]string{producer.brokerAddress}, producer.config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create producer: %v", err)
 	}
+	syncProducer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create producer: %v", err)
+	}
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	producer.client = client
+	producer.producer = syncProducer
+	return syncProducer, nil
+}
 
-	return &KafkaProducer{
-		producer: producer,
-	}, nil
+func (producer *KafkaProducer) current() (sarama.SyncProducer, sarama.Client) {
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	return producer.producer, producer.client
+}
+
+// Check reports whether the broker can be reached. The broker is probed in the
+// background, by one check at a time, since sarama's own timeouts are far
+// longer than a health check may take; Check gives up when ctx is done.
+func (producer *KafkaProducer) Check(ctx context.Context) error {
+	producer.mu.Lock()
+	checked := producer.checked
+	if checked == nil {
+		checked = make(chan struct{})
+		producer.checked = checked
+		go producer.check(checked)
+	}
+	producer.mu.Unlock()
+
+	select {
+	case <-checked:
+		producer.mu.Lock()
+		defer producer.mu.Unlock()
+		return producer.checkErr
+	case <-ctx.Done():
+		return fmt.Errorf("failed to reach Kafka: %w", ctx.Err())
+	}
+}
+
+func (producer *KafkaProducer) check(checked chan struct{}) {
+	err := producer.probe()
+	producer.mu.Lock()
+	producer.checkErr = err
+	producer.checked = nil
+	producer.mu.Unlock()
+	close(checked)
+}
+
+func (producer *KafkaProducer) probe() error {
+	if _, err := producer.connected(); err != nil {
+		return err
+	}
+	_, client := producer.current()
+	if err := client.RefreshMetadata(); err != nil {
+		return fmt.Errorf("failed to reach Kafka: %v", err)
+	}
+	return nil
+}
+
+func newProducerConfig(partitioner Partitioner) (*sarama.Config, error) {
+	constructor, err := partitioner.constructor()
+	if err != nil {
+		return nil, err
+	}
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Partitioner = constructor
+	return config, nil
 }
 
 func (producer *KafkaProducer) Close() error {
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	if producer.producer == nil {
+		return nil
+	}
 	err := producer.producer.Close()
 	if err != nil {
 		return fmt.Errorf("failed to close producer")
 	}
+	if producer.client != nil && producer.client.Close() != nil {
+		return fmt.Errorf("failed to close producer")
+	}
 	return nil
 }
 
+// SetEncoding selects the wire format of events, protobuf by default.
+func (producer *KafkaProducer) SetEncoding(encoding Encoding) {
+	producer.encoding = encoding
+}
+
 func (producer *KafkaProducer) SendEvent(topic string, event Event) error {
-	message := &sarama.ProducerMessage{
-		Topic: topic,
-		Key:   sarama.StringEncoder("key"),
-		Value: sarama.StringEncoder(fmt.Sprintf("EventType: %s, EventRequestBody: %s, EventTime: %v",
-			event.Type, event.RequestBody, event.TimeStamp)),
+	message, err := newMessage(topic, event, producer.encoding)
+	if err != nil {
+		return err
 	}
 
-	_, _, err := producer.producer.SendMessage(message)
+	return producer.SendMessage(message)
+}
+
+// SendMessage sends an already encoded message, such as one moved to a retry
+// topic.
+func (producer *KafkaProducer) SendMessage(message *sarama.ProducerMessage) error {
+	syncProducer, err := producer.connected()
+	if err != nil {
+		return err
+	}
+	_, _, err = syncProducer.SendMessage(message)
 	if err != nil {
 		return fmt.Errorf("failed to send message to Kafka: %v", err)
 	}
-
 	return nil
 }
+
+// newMessage encodes event into a message keyed by its article, generating
+// the event id when it is empty.
+func newMessage(topic string, event Event, encoding Encoding) (*sarama.ProducerMessage, error) {
+	if event.ID == "" {
+		id, err := newEventID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate event id: %v", err)
+		}
+		event.ID = id
+	}
+	value, headers, err := encodeEvent(event, encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %v", err)
+	}
+	return &sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.StringEncoder(messageKey(event)),
+		Value:   sarama.ByteEncoder(value),
+		Headers: headers,
+	}, nil
+}
+
+// messageKey is the id of the article of event. Kafka keeps the messages of a
+// key in one partition, so the events of an article are consumed in order.
+func messageKey(event Event) string {
+	return strconv.FormatInt(event.AggregateID, 10)
+}
@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaProducer_SendEvent_KeyedByArticle(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	config, err := newProducerConfig(PartitionerHash)
+	require.NoError(t, err)
+	syncProducer := mocks.NewSyncProducer(t, config)
+	defer syncProducer.Close()
+	for _, key := range []string{"10", "11", "10"} {
+		key := key
+		syncProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(message *sarama.ProducerMessage) error {
+			encoded, err := message.Key.Encode()
+			if err != nil {
+				return err
+			}
+			if string(encoded) != key {
+				return errors.New("unexpected key " + string(encoded))
+			}
+			return nil
+		})
+	}
+	producer := &KafkaProducer{producer: syncProducer}
+
+	// act & assert
+	for _, id := range []int64{10, 11, 10} {
+		assert.NoError(t, producer.SendEvent("crud", Event{Type: events.EventType_EVENT_TYPE_ARTICLE_UPDATED, AggregateID: id}))
+	}
+}
+
+func TestKafkaProducer_SendEvent_Error(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	config, err := newProducerConfig(PartitionerHash)
+	require.NoError(t, err)
+	syncProducer := mocks.NewSyncProducer(t, config)
+	defer syncProducer.Close()
+	syncProducer.ExpectSendMessageAndFail(sarama.ErrNotLeaderForPartition)
+	producer := &KafkaProducer{producer: syncProducer}
+
+	// act
+	err = producer.SendEvent("crud", Event{AggregateID: 10})
+
+	// assert
+	assert.Error(t, err)
+}
+
+func TestPartitioners(t *testing.T) {
+	t.Parallel()
+
+	const numPartitions = 8
+	for _, name := range []string{"hash", "reference", "crc32"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			partitioner, err := ParsePartitioner(name)
+			require.NoError(t, err)
+			config, err := newProducerConfig(partitioner)
+			require.NoError(t, err)
+			partition := func(id int64) int32 {
+				message := &sarama.ProducerMessage{Topic: "crud", Key: sarama.StringEncoder(messageKey(Event{AggregateID: id}))}
+				chosen, err := config.Producer.Partitioner("crud").Partition(message, numPartitions)
+				require.NoError(t, err)
+				return chosen
+			}
+
+			used := make(map[int32]bool)
+			for id := int64(1); id <= 100; id++ {
+				assert.Equal(t, partition(id), partition(id))
+				used[partition(id)] = true
+			}
+			assert.Greater(t, len(used), 1)
+		})
+	}
+}
+
+func TestParsePartitioner_Unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParsePartitioner("roundrobin")
+	assert.Error(t, err)
+	_, err = newProducerConfig(Partitioner(42))
+	assert.Error(t, err)
+}
+
+func TestKafkaProducer_Check_BoundedByContext(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		// accept connections but never answer, like a stuck broker
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	config, err := newProducerConfig(PartitionerHash)
+	require.NoError(t, err)
+	producer := &KafkaProducer{brokerAddress: listener.Addr().String(), config: config}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// act
+	start := time.Now()
+	err = producer.Check(ctx)
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
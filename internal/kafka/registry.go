@@ -0,0 +1,183 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// EventHandler processes the events of the types it was registered for.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event *events.ArticleEvent) error
+}
+
+// EventHandlerFunc adapts a function to EventHandler.
+type EventHandlerFunc func(ctx context.Context, event *events.ArticleEvent) error
+
+func (handler EventHandlerFunc) HandleEvent(ctx context.Context, event *events.ArticleEvent) error {
+	return handler(ctx, event)
+}
+
+// LogEvent is an EventHandler that logs the events it gets.
+var LogEvent = EventHandlerFunc(func(ctx context.Context, event *events.ArticleEvent) error {
+	logger.Infof(ctx, "received event: %s", protojson.Format(event))
+	return nil
+})
+
+// HandlerStats are the counters of one registered handler.
+type HandlerStats struct {
+	Processed int64
+	Failed    int64
+	Retries   int64
+}
+
+type registeredHandler struct {
+	name      string
+	handler   EventHandler
+	processed atomic.Int64
+	failed    atomic.Int64
+	retries   atomic.Int64
+}
+
+// Registry is a MessageHandler that dispatches every event to the handlers
+// registered for its type. A failing handler is retried on its own and does
+// not keep the other handlers from getting the event. A message naming the
+// handlers that failed on it in its HeaderFailedHandlers header, as sent to
+// the retry and dead-letter topics, is only passed to those handlers, so the
+// others do not handle it twice.
+type Registry struct {
+	retry RetryPolicy
+
+	mu       sync.RWMutex
+	handlers map[events.EventType][]*registeredHandler
+	byName   map[string]*registeredHandler
+}
+
+func NewRegistry(retry RetryPolicy) *Registry {
+	return &Registry{
+		retry:    retry,
+		handlers: make(map[events.EventType][]*registeredHandler),
+		byName:   make(map[string]*registeredHandler),
+	}
+}
+
+// Register adds handler for eventTypes, or for all event types when none are
+// given. Names identify handlers in logs and stats and must be unique.
+func (registry *Registry) Register(name string, handler EventHandler, eventTypes ...events.EventType) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.byName[name]; ok {
+		return fmt.Errorf("event handler %q is already registered", name)
+	}
+
+	if len(eventTypes) == 0 {
+		for value := range events.EventType_name {
+			if eventType := events.EventType(value); eventType != events.EventType_EVENT_TYPE_UNSPECIFIED {
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+	}
+	registered := &registeredHandler{name: name, handler: handler}
+	registry.byName[name] = registered
+	for _, eventType := range eventTypes {
+		registry.handlers[eventType] = append(registry.handlers[eventType], registered)
+	}
+	return nil
+}
+
+// Stats returns the counters of every registered handler by name.
+func (registry *Registry) Stats() map[string]HandlerStats {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	stats := make(map[string]HandlerStats, len(registry.byName))
+	for name, registered := range registry.byName {
+		stats[name] = HandlerStats{
+			Processed: registered.processed.Load(),
+			Failed:    registered.failed.Load(),
+			Retries:   registered.retries.Load(),
+		}
+	}
+	return stats
+}
+
+// HandleMessage decodes message and passes the event to its handlers. It
+// returns the errors of the handlers that still failed after their retries,
+// or a permanent error when the message cannot be decoded.
+func (registry *Registry) HandleMessage(ctx context.Context, message *sarama.ConsumerMessage) error {
+	event, err := DecodeEvent(message)
+	if err != nil {
+		return Permanent(err)
+	}
+	only, selective := failedHandlers(message)
+
+	registry.mu.RLock()
+	handlers := registry.handlers[event.Type]
+	registry.mu.RUnlock()
+
+	var errs []error
+	var failed []string
+	for _, registered := range handlers {
+		if selective && !only[registered.name] {
+			continue
+		}
+		if err = registry.handle(ctx, registered, event); err != nil {
+			logger.Errorf(ctx, "event handler %s failed on event %s: %v", registered.name, event.EventId, err)
+			errs = append(errs, fmt.Errorf("%s: %w", registered.name, err))
+			failed = append(failed, registered.name)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &handlersError{handlers: failed, err: errors.Join(errs...)}
+}
+
+// handlersError is the error of the handlers that failed on a message.
+type handlersError struct {
+	handlers []string
+	err      error
+}
+
+func (err *handlersError) Error() string {
+	return err.err.Error()
+}
+
+func (err *handlersError) Unwrap() error {
+	return err.err
+}
+
+// failedHandlers returns the names listed in the HeaderFailedHandlers header
+// of message, and whether it has one.
+func failedHandlers(message *sarama.ConsumerMessage) (map[string]bool, bool) {
+	value, ok := headerValue(message.Headers, HeaderFailedHandlers)
+	if !ok {
+		return nil, false
+	}
+	names := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		names[name] = true
+	}
+	return names, true
+}
+
+func (registry *Registry) handle(ctx context.Context, registered *registeredHandler, event *events.ArticleEvent) error {
+	err := registry.retry.do(ctx, func() error {
+		return registered.handler.HandleEvent(ctx, event)
+	}, func() {
+		registered.retries.Add(1)
+	})
+	if err != nil {
+		registered.failed.Add(1)
+		return err
+	}
+	registered.processed.Add(1)
+	return nil
+}
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func eventMessage(t *testing.T, eventType events.EventType) *sarama.ConsumerMessage {
+	value, err := proto.Marshal(&events.ArticleEvent{EventId: "1", Type: eventType, AggregateId: 10})
+	require.NoError(t, err)
+	return &sarama.ConsumerMessage{Value: value}
+}
+
+func TestRegistry_HandleMessage(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	registry := NewRegistry(RetryPolicy{MaxAttempts: 3})
+	var audited, invalidated []events.EventType
+	require.NoError(t, registry.Register("audit", EventHandlerFunc(func(_ context.Context, event *events.ArticleEvent) error {
+		audited = append(audited, event.Type)
+		return nil
+	})))
+	attempts := 0
+	require.NoError(t, registry.Register("cache", EventHandlerFunc(func(_ context.Context, event *events.ArticleEvent) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("cache unavailable")
+		}
+		invalidated = append(invalidated, event.Type)
+		return nil
+	}), events.EventType_EVENT_TYPE_ARTICLE_UPDATED))
+
+	// act
+	errCreated := registry.HandleMessage(context.Background(), eventMessage(t, events.EventType_EVENT_TYPE_ARTICLE_CREATED))
+	errUpdated := registry.HandleMessage(context.Background(), eventMessage(t, events.EventType_EVENT_TYPE_ARTICLE_UPDATED))
+
+	// assert
+	assert.NoError(t, errCreated)
+	assert.NoError(t, errUpdated)
+	assert.Equal(t, []events.EventType{events.EventType_EVENT_TYPE_ARTICLE_CREATED, events.EventType_EVENT_TYPE_ARTICLE_UPDATED}, audited)
+	assert.Equal(t, []events.EventType{events.EventType_EVENT_TYPE_ARTICLE_UPDATED}, invalidated)
+	assert.Equal(t, map[string]HandlerStats{
+		"audit": {Processed: 2},
+		"cache": {Processed: 1, Retries: 1},
+	}, registry.Stats())
+}
+
+func TestRegistry_HandleMessage_Failed(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	registry := NewRegistry(RetryPolicy{MaxAttempts: 2})
+	handlerErr := errors.New("index unavailable")
+	require.NoError(t, registry.Register("search", EventHandlerFunc(func(context.Context, *events.ArticleEvent) error {
+		return handlerErr
+	})))
+	audited := 0
+	require.NoError(t, registry.Register("audit", EventHandlerFunc(func(context.Context, *events.ArticleEvent) error {
+		audited++
+		return nil
+	})))
+
+	// act
+	err := registry.HandleMessage(context.Background(), eventMessage(t, events.EventType_EVENT_TYPE_ARTICLE_DELETED))
+
+	// assert
+	assert.ErrorIs(t, err, handlerErr)
+	assert.Equal(t, 1, audited)
+	assert.Equal(t, HandlerStats{Failed: 1, Retries: 1}, registry.Stats()["search"])
+}
+
+func TestRegistry_Register_Duplicate(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(DefaultRetryPolicy)
+	require.NoError(t, registry.Register("audit", LogEvent))
+
+	assert.Error(t, registry.Register("audit", LogEvent))
+}
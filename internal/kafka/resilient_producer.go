@@ -0,0 +1,148 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+)
+
+// ResilienceConfig configures a ResilientProducer.
+type ResilienceConfig struct {
+	Retry RetryPolicy
+	// FailureThreshold is the number of consecutive failed sends that opens
+	// the circuit breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before Kafka is probed
+	// again.
+	OpenTimeout time.Duration
+	// ReplayInterval is how often the spool is replayed while it is not empty.
+	ReplayInterval time.Duration
+}
+
+// DefaultResilienceConfig retries a send for about a tenth of a second and
+// stops sending for ten seconds after five failed ones.
+func DefaultResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		Retry: RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: 20 * time.Millisecond,
+			MaxBackoff:     100 * time.Millisecond,
+		},
+		FailureThreshold: 5,
+		OpenTimeout:      10 * time.Second,
+		ReplayInterval:   time.Second,
+	}
+}
+
+// ResilientProducer sends events with producer, retrying failed sends. Events
+// that cannot be sent, and all events while earlier ones are still spooled,
+// are written to the spool and replayed in order once Kafka is back, so
+// SendEvent fails only when the spool does.
+type ResilientProducer struct {
+	producer KafkaInterface
+	spool    *Spool
+	breaker  *CircuitBreaker
+	config   ResilienceConfig
+
+	// sending serializes the sends, from checking the spool to spooling the
+	// event, so an event cannot be sent while an earlier one is about to be
+	// spooled.
+	sending sync.Mutex
+	// replaying serializes the replays. Sends do not wait for a replay: while
+	// the spool is not empty they append to it, so no event overtakes the
+	// spooled ones, and the spool only gets empty once the replay sent them.
+	replaying sync.Mutex
+}
+
+func NewResilientProducer(producer KafkaInterface, spool *Spool, config ResilienceConfig) *ResilientProducer {
+	return &ResilientProducer{
+		producer: producer,
+		spool:    spool,
+		breaker:  NewCircuitBreaker(config.FailureThreshold, config.OpenTimeout),
+		config:   config,
+	}
+}
+
+func (producer *ResilientProducer) SendEvent(topic string, event Event) error {
+	// The id is fixed before the event is spooled, so consumers can tell a
+	// replayed event from a new one.
+	if event.ID == "" {
+		id, err := newEventID()
+		if err != nil {
+			return fmt.Errorf("failed to generate event id: %v", err)
+		}
+		event.ID = id
+	}
+
+	producer.sending.Lock()
+	defer producer.sending.Unlock()
+	if producer.spool.Len() == 0 && producer.breaker.Allow() {
+		err := producer.send(topic, event)
+		if err == nil {
+			return nil
+		}
+		logger.Errorf(context.Background(), "failed to send event %s, spooling it: %v", event.ID, err)
+	}
+	return producer.spool.Append(topic, event)
+}
+
+// Run replays the spool until ctx is done.
+func (producer *ResilientProducer) Run(ctx context.Context) {
+	ticker := time.NewTicker(producer.config.ReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := producer.replay(); err != nil {
+			logger.Errorf(ctx, "failed to replay spooled events: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replay sends the spooled events in order, stopping at the first one that
+// cannot be sent. Unreadable events are dropped. An event is only removed from
+// the spool once it was sent.
+func (producer *ResilientProducer) replay() error {
+	producer.replaying.Lock()
+	defer producer.replaying.Unlock()
+	for producer.spool.Len() > 0 {
+		topic, event, _, err := producer.spool.Peek()
+		if err != nil {
+			logger.Errorf(context.Background(), "dropping spooled event: %v", err)
+			if err = producer.spool.Pop(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !producer.breaker.Allow() {
+			return nil
+		}
+		if err = producer.send(topic, event); err != nil {
+			return err
+		}
+		if err = producer.spool.Pop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send sends event with retries and reports the outcome to the breaker.
+func (producer *ResilientProducer) send(topic string, event Event) error {
+	err := producer.config.Retry.do(context.Background(), func() error {
+		return producer.producer.SendEvent(topic, event)
+	}, func() {})
+	if err != nil {
+		producer.breaker.Failure()
+		return err
+	}
+	producer.breaker.Success()
+	return nil
+}
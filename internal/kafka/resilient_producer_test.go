@@ -0,0 +1,205 @@
+package kafka
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyProducer fails every send while down.
+type flakyProducer struct {
+	mu    sync.Mutex
+	down  bool
+	calls int
+	sent  []int64
+}
+
+func (producer *flakyProducer) SendEvent(_ string, event Event) error {
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	producer.calls++
+	if producer.down {
+		return errors.New("broker unavailable")
+	}
+	producer.sent = append(producer.sent, event.AggregateID)
+	return nil
+}
+
+func TestResilientProducer(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	spool, err := OpenSpool(t.TempDir())
+	require.NoError(t, err)
+	kafka := &flakyProducer{down: true}
+	config := ResilienceConfig{Retry: RetryPolicy{MaxAttempts: 2}, FailureThreshold: 1, OpenTimeout: time.Hour}
+	producer := NewResilientProducer(kafka, spool, config)
+
+	// act
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, producer.SendEvent("crud", Event{Type: events.EventType_EVENT_TYPE_ARTICLE_VIEWED, AggregateID: id}))
+	}
+	callsWhileOpen := kafka.calls
+	kafka.down = false
+	producer.breaker.now = func() time.Time { return time.Now().Add(time.Hour) }
+	replayErr := producer.replay()
+	sendErr := producer.SendEvent("crud", Event{AggregateID: 4})
+
+	// assert
+	assert.Equal(t, 2, callsWhileOpen)
+	assert.NoError(t, replayErr)
+	assert.NoError(t, sendErr)
+	assert.Equal(t, []int64{1, 2, 3, 4}, kafka.sent)
+	assert.Equal(t, 0, spool.Len())
+}
+
+func TestSpool_Reopen(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	dir := t.TempDir()
+	spool, err := OpenSpool(dir)
+	require.NoError(t, err)
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, spool.Append("crud", Event{ID: "event", AggregateID: id, Time: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}))
+	}
+	require.NoError(t, spool.Pop())
+
+	// act
+	reopened, err := OpenSpool(dir)
+	require.NoError(t, err)
+	topic, event, ok, err := reopened.Peek()
+	require.NoError(t, reopened.Append("crud", Event{AggregateID: 4}))
+
+	// assert
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "crud", topic)
+	assert.Equal(t, Event{ID: "event", AggregateID: 2, Time: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}, event)
+	assert.Equal(t, 3, reopened.Len())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	assert.True(t, breaker.Allow())
+	breaker.Failure()
+	assert.True(t, breaker.Open())
+	assert.False(t, breaker.Allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.Allow())
+	assert.False(t, breaker.Allow(), "only one probe at a time")
+	breaker.Success()
+	assert.False(t, breaker.Open())
+	assert.True(t, breaker.Allow())
+}
+
+// slowProducer blocks every send until release is closed.
+type slowProducer struct {
+	flakyProducer
+	sending chan struct{}
+	release chan struct{}
+}
+
+func (producer *slowProducer) SendEvent(topic string, event Event) error {
+	producer.sending <- struct{}{}
+	<-producer.release
+	return producer.flakyProducer.SendEvent(topic, event)
+}
+
+func TestResilientProducer_SendDuringReplay(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	spool, err := OpenSpool(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, spool.Append("crud", Event{ID: "spooled", AggregateID: 1}))
+	kafka := &slowProducer{sending: make(chan struct{}, 1), release: make(chan struct{})}
+	producer := NewResilientProducer(kafka, spool, DefaultResilienceConfig())
+	replayed := make(chan error)
+	go func() {
+		replayed <- producer.replay()
+	}()
+	<-kafka.sending
+
+	// act
+	sendErr := producer.SendEvent("crud", Event{AggregateID: 2})
+	close(kafka.release)
+	replayErr := <-replayed
+
+	// assert
+	assert.NoError(t, sendErr, "the event is spooled instead of waiting for the replay")
+	assert.NoError(t, replayErr)
+	assert.Equal(t, []int64{1, 2}, kafka.sent)
+	assert.Equal(t, 0, spool.Len())
+}
+
+// gatedProducer blocks the send of the gated event until release is closed
+// and then fails it, recording the ids of the events it sent.
+type gatedProducer struct {
+	mu      sync.Mutex
+	gated   string
+	failed  bool
+	sending chan struct{}
+	release chan struct{}
+	sent    []string
+}
+
+func (producer *gatedProducer) SendEvent(_ string, event Event) error {
+	producer.mu.Lock()
+	gated := event.ID == producer.gated && !producer.failed
+	producer.failed = producer.failed || gated
+	producer.mu.Unlock()
+	if gated {
+		producer.sending <- struct{}{}
+		<-producer.release
+		return errors.New("broker unavailable")
+	}
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	producer.sent = append(producer.sent, event.ID)
+	return nil
+}
+
+func TestResilientProducer_ConcurrentSendsKeepOrder(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	spool, err := OpenSpool(t.TempDir())
+	require.NoError(t, err)
+	kafka := &gatedProducer{gated: "first", sending: make(chan struct{}, 1), release: make(chan struct{})}
+	config := ResilienceConfig{Retry: RetryPolicy{MaxAttempts: 1}, FailureThreshold: 10, OpenTimeout: time.Hour}
+	producer := NewResilientProducer(kafka, spool, config)
+	firstErr, secondErr := make(chan error), make(chan error)
+
+	// act
+	go func() {
+		firstErr <- producer.SendEvent("crud", Event{ID: "first", AggregateID: 1})
+	}()
+	<-kafka.sending
+	go func() {
+		secondErr <- producer.SendEvent("crud", Event{ID: "second", AggregateID: 1})
+	}()
+	// give the second send the chance to overtake the first one
+	time.Sleep(50 * time.Millisecond)
+	close(kafka.release)
+	require.NoError(t, <-firstErr)
+	require.NoError(t, <-secondErr)
+	replayErr := producer.replay()
+
+	// assert
+	assert.NoError(t, replayErr)
+	assert.Equal(t, []string{"first", "second"}, kafka.sent)
+	assert.Equal(t, 0, spool.Len())
+}
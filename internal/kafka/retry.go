@@ -0,0 +1,42 @@
+package kafka
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy is how a failed operation is retried. The backoff doubles after
+// every attempt up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy tries an operation three times over about a third of a
+// second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
+
+// do calls operation until it succeeds, the attempts run out or ctx is done,
+// calling retried before every retry. It returns the last error.
+func (policy RetryPolicy) do(ctx context.Context, operation func() error, retried func()) error {
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		retried()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, policy.MaxBackoff)
+	}
+}
@@ -0,0 +1,142 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const spoolExtension = ".json"
+
+// spooledEvent is the content of a spool file.
+type spooledEvent struct {
+	Topic string `json:"topic"`
+	Event Event  `json:"event"`
+}
+
+// Spool is a durable FIFO queue of events kept in a directory, one file per
+// event named after its sequence number. A file is synced before Append
+// returns, so spooled events survive a crash.
+type Spool struct {
+	dir string
+
+	mu    sync.Mutex
+	queue []uint64
+	next  uint64
+}
+
+// OpenSpool opens the spool in dir, creating the directory if needed. Events
+// left by an earlier run are kept in their order.
+func OpenSpool(dir string) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool: %w", err)
+	}
+
+	spool := &Spool{dir: dir, next: 1}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), spoolExtension)
+		if !ok {
+			continue
+		}
+		sequence, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		spool.queue = append(spool.queue, sequence)
+		spool.next = max(spool.next, sequence+1)
+	}
+	sort.Slice(spool.queue, func(i, j int) bool { return spool.queue[i] < spool.queue[j] })
+	return spool, nil
+}
+
+// Len returns the number of spooled events.
+func (spool *Spool) Len() int {
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	return len(spool.queue)
+}
+
+// Append adds event to the end of the spool.
+func (spool *Spool) Append(topic string, event Event) error {
+	data, err := json.Marshal(spooledEvent{Topic: topic, Event: event})
+	if err != nil {
+		return fmt.Errorf("failed to encode spooled event: %w", err)
+	}
+
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	sequence := spool.next
+	path := spool.path(sequence)
+	temp := path + ".tmp"
+	if err = writeSynced(temp, data); err != nil {
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+	if err = os.Rename(temp, path); err != nil {
+		return fmt.Errorf("failed to spool event: %w", err)
+	}
+	spool.next++
+	spool.queue = append(spool.queue, sequence)
+	return nil
+}
+
+// Peek returns the oldest event. ok is false when the spool is empty.
+func (spool *Spool) Peek() (topic string, event Event, ok bool, err error) {
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	if len(spool.queue) == 0 {
+		return "", Event{}, false, nil
+	}
+
+	data, err := os.ReadFile(spool.path(spool.queue[0]))
+	if err != nil {
+		return "", Event{}, false, fmt.Errorf("failed to read spooled event: %w", err)
+	}
+	var spooled spooledEvent
+	if err = json.Unmarshal(data, &spooled); err != nil {
+		return "", Event{}, false, fmt.Errorf("failed to decode spooled event %d: %w", spool.queue[0], err)
+	}
+	return spooled.Topic, spooled.Event, true, nil
+}
+
+// Pop removes the oldest event.
+func (spool *Spool) Pop() error {
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	if len(spool.queue) == 0 {
+		return nil
+	}
+	if err := os.Remove(spool.path(spool.queue[0])); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove spooled event: %w", err)
+	}
+	spool.queue = spool.queue[1:]
+	return nil
+}
+
+func (spool *Spool) path(sequence uint64) string {
+	return filepath.Join(spool.dir, fmt.Sprintf("%020d%s", sequence, spoolExtension))
+}
+
+func writeSynced(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStats are the statistics of a connection pool, as reported by
+// *pgxpool.Stat.
+type PoolStats interface {
+	AcquiredConns() int32
+	IdleConns() int32
+	TotalConns() int32
+	MaxConns() int32
+	AcquireCount() int64
+	EmptyAcquireCount() int64
+	AcquireDuration() time.Duration
+}
+
+var (
+	poolAcquiredDesc = prometheus.NewDesc("db_pool_acquired_connections",
+		"Number of connections currently in use.", nil, nil)
+	poolIdleDesc = prometheus.NewDesc("db_pool_idle_connections",
+		"Number of idle connections in the pool.", nil, nil)
+	poolTotalDesc = prometheus.NewDesc("db_pool_total_connections",
+		"Number of connections in the pool, including those being opened.", nil, nil)
+	poolMaxDesc = prometheus.NewDesc("db_pool_max_connections",
+		"Maximum size of the pool.", nil, nil)
+	poolAcquiresDesc = prometheus.NewDesc("db_pool_acquires_total",
+		"Number of connections acquired from the pool.", nil, nil)
+	poolWaitsDesc = prometheus.NewDesc("db_pool_empty_acquires_total",
+		"Number of acquires that waited for a connection because none was idle.", nil, nil)
+	poolWaitDurationDesc = prometheus.NewDesc("db_pool_acquire_duration_seconds_total",
+		"Total time spent acquiring connections from the pool.", nil, nil)
+)
+
+// poolCollector reads the statistics of the pool on every scrape.
+type poolCollector struct {
+	stats func() PoolStats
+}
+
+// NewPoolCollector creates a collector of the statistics returned by stats,
+// such as func() PoolStats { return pool.Stat() }.
+func NewPoolCollector(stats func() PoolStats) prometheus.Collector {
+	return &poolCollector{stats: stats}
+}
+
+func (collector *poolCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- poolAcquiredDesc
+	descs <- poolIdleDesc
+	descs <- poolTotalDesc
+	descs <- poolMaxDesc
+	descs <- poolAcquiresDesc
+	descs <- poolWaitsDesc
+	descs <- poolWaitDurationDesc
+}
+
+func (collector *poolCollector) Collect(metrics chan<- prometheus.Metric) {
+	stats := collector.stats()
+	metrics <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(stats.AcquiredConns()))
+	metrics <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(stats.IdleConns()))
+	metrics <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(stats.TotalConns()))
+	metrics <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(stats.MaxConns()))
+	metrics <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(stats.AcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(poolWaitsDesc, prometheus.CounterValue, float64(stats.EmptyAcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(poolWaitDurationDesc, prometheus.CounterValue, stats.AcquireDuration().Seconds())
+}
+
+const (
+	// countInterval is how often the articles are counted. Counting scans the
+	// table, so it is not done on every scrape.
+	countInterval = 30 * time.Second
+	// countTimeout bounds the query behind the article count.
+	countTimeout = 2 * time.Second
+)
+
+var articlesDesc = prometheus.NewDesc("articles",
+	"Number of live articles.", nil, nil)
+
+// ArticleCollector reports the number of live articles as counted by its Run
+// loop, so scrapes do not query the database.
+type ArticleCollector struct {
+	count    func(ctx context.Context) (int64, error)
+	interval time.Duration
+
+	mu      sync.Mutex
+	counted bool
+	value   int64
+}
+
+// NewArticleCollector creates a collector of the number of live articles,
+// which are counted by count. The gauge is left out of scrapes until the
+// articles are counted, and while the last count failed.
+func NewArticleCollector(count func(ctx context.Context) (int64, error)) *ArticleCollector {
+	return &ArticleCollector{count: count, interval: countInterval}
+}
+
+// Run counts the articles right away and then once per interval until ctx is
+// done.
+func (collector *ArticleCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(collector.interval)
+	defer ticker.Stop()
+
+	for {
+		collector.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (collector *ArticleCollector) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, countTimeout)
+	defer cancel()
+	count, err := collector.count(ctx)
+	if err != nil && ctx.Err() == nil {
+		logger.Errorf(ctx, "failed to count articles: %v", err)
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.counted = err == nil
+	collector.value = count
+}
+
+func (collector *ArticleCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- articlesDesc
+}
+
+func (collector *ArticleCollector) Collect(metrics chan<- prometheus.Metric) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if collector.counted {
+		metrics <- prometheus.MustNewConstMetric(articlesDesc, prometheus.GaugeValue, float64(collector.value))
+	}
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/kafka"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// KafkaMetrics observes the events sent by the producers and the lag of the
+// consumer. It implements kafka.LagObserver.
+type KafkaMetrics struct {
+	sendLatency *prometheus.HistogramVec
+	sendErrors  *prometheus.CounterVec
+	lag         *prometheus.GaugeVec
+}
+
+// NewKafkaMetrics creates the Kafka collectors and registers them with
+// registerer.
+func NewKafkaMetrics(registerer prometheus.Registerer) *KafkaMetrics {
+	metrics := &KafkaMetrics{
+		sendLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "kafka_producer_send_seconds",
+			Help:    "Latency of sending events, as seen by the caller.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"producer", "topic"}),
+		sendErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "kafka_producer_send_failures_total",
+			Help: "Number of events that failed to be sent.",
+		}, []string{"producer", "topic"}),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "kafka_consumer_lag",
+			Help: "Number of messages of a partition the consumer has yet to handle.",
+		}, []string{"topic", "partition"}),
+	}
+	registerer.MustRegister(metrics.sendLatency, metrics.sendErrors, metrics.lag)
+	return metrics
+}
+
+// Producer wraps producer so its sends are observed under name.
+func (metrics *KafkaMetrics) Producer(name string, producer kafka.KafkaInterface) kafka.KafkaInterface {
+	return &instrumentedProducer{name: name, producer: producer, metrics: metrics}
+}
+
+func (metrics *KafkaMetrics) ObserveLag(topic string, partition int32, lag int64) {
+	metrics.lag.WithLabelValues(topic, strconv.Itoa(int(partition))).Set(float64(lag))
+}
+
+// ForgetLag drops the lag of a partition, so a revoked partition is not
+// reported with a stale lag.
+func (metrics *KafkaMetrics) ForgetLag(topic string, partition int32) {
+	metrics.lag.DeleteLabelValues(topic, strconv.Itoa(int(partition)))
+}
+
+type instrumentedProducer struct {
+	name     string
+	producer kafka.KafkaInterface
+	metrics  *KafkaMetrics
+}
+
+func (producer *instrumentedProducer) SendEvent(topic string, event kafka.Event) error {
+	start := time.Now()
+	err := producer.producer.SendEvent(topic, event)
+	producer.metrics.sendLatency.WithLabelValues(producer.name, topic).Observe(time.Since(start).Seconds())
+	if err != nil {
+		producer.metrics.sendErrors.WithLabelValues(producer.name, topic).Inc()
+	}
+	return err
+}
+
+// handlerCollector reads the counters of the event handlers on every scrape.
+type handlerCollector struct {
+	stats     func() map[string]kafka.HandlerStats
+	processed *prometheus.Desc
+	failed    *prometheus.Desc
+	retries   *prometheus.Desc
+}
+
+// NewHandlerCollector creates a collector of the counters returned by stats,
+// such as registry.Stats, labeled with the name of the consumer the handlers
+// belong to.
+func NewHandlerCollector(consumer string, stats func() map[string]kafka.HandlerStats) prometheus.Collector {
+	labels := prometheus.Labels{"consumer": consumer}
+	return &handlerCollector{
+		stats: stats,
+		processed: prometheus.NewDesc("kafka_handler_processed_total",
+			"Number of events handled successfully.", []string{"handler"}, labels),
+		failed: prometheus.NewDesc("kafka_handler_failures_total",
+			"Number of events that still failed after their retries.", []string{"handler"}, labels),
+		retries: prometheus.NewDesc("kafka_handler_retries_total",
+			"Number of retried attempts to handle events.", []string{"handler"}, labels),
+	}
+}
+
+func (collector *handlerCollector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- collector.processed
+	descs <- collector.failed
+	descs <- collector.retries
+}
+
+func (collector *handlerCollector) Collect(metrics chan<- prometheus.Metric) {
+	for name, stats := range collector.stats() {
+		metrics <- prometheus.MustNewConstMetric(collector.processed, prometheus.CounterValue, float64(stats.Processed), name)
+		metrics <- prometheus.MustNewConstMetric(collector.failed, prometheus.CounterValue, float64(stats.Failed), name)
+		metrics <- prometheus.MustNewConstMetric(collector.retries, prometheus.CounterValue, float64(stats.Retries), name)
+	}
+}
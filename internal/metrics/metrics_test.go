@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/interceptors"
+	"github.com/NRKA/gRPC-Server/internal/kafka"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCMetrics(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	registry := prometheus.NewRegistry()
+	metrics := NewRPCMetrics(registry)
+	info := &grpc.UnaryServerInfo{FullMethod: "/ArticleService/GetArticle"}
+	ok := func(context.Context, interface{}) (interface{}, error) { return "article", nil }
+	notFound := func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "article not found")
+	}
+	panics := func(context.Context, interface{}) (interface{}, error) { panic("boom") }
+
+	// act
+	for _, handler := range []grpc.UnaryHandler{ok, ok, notFound} {
+		_, _ = metrics.UnaryInterceptor(context.Background(), "request", info, handler)
+	}
+	_, panicErr := metrics.UnaryInterceptor(context.Background(), "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return interceptors.UnaryRecovery(ctx, req, info, panics)
+	})
+
+	// assert
+	assert.Equal(t, codes.Internal, status.Code(panicErr))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.handled.WithLabelValues(info.FullMethod, "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.handled.WithLabelValues(info.FullMethod, "NotFound")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.handled.WithLabelValues(info.FullMethod, "Internal")))
+	assert.Equal(t, 3, testutil.CollectAndCount(metrics.latency))
+	count, err := testutil.GatherAndCount(registry, "grpc_server_panics_recovered_total")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+type fakePoolStats struct{}
+
+func (fakePoolStats) AcquiredConns() int32           { return 3 }
+func (fakePoolStats) IdleConns() int32               { return 1 }
+func (fakePoolStats) TotalConns() int32              { return 4 }
+func (fakePoolStats) MaxConns() int32                { return 10 }
+func (fakePoolStats) AcquireCount() int64            { return 100 }
+func (fakePoolStats) EmptyAcquireCount() int64       { return 7 }
+func (fakePoolStats) AcquireDuration() time.Duration { return 1500 * time.Millisecond }
+
+func TestPoolCollector(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	collector := NewPoolCollector(func() PoolStats { return fakePoolStats{} })
+	expected := `
+# HELP db_pool_acquired_connections Number of connections currently in use.
+# TYPE db_pool_acquired_connections gauge
+db_pool_acquired_connections 3
+# HELP db_pool_acquire_duration_seconds_total Total time spent acquiring connections from the pool.
+# TYPE db_pool_acquire_duration_seconds_total counter
+db_pool_acquire_duration_seconds_total 1.5
+# HELP db_pool_empty_acquires_total Number of acquires that waited for a connection because none was idle.
+# TYPE db_pool_empty_acquires_total counter
+db_pool_empty_acquires_total 7
+# HELP db_pool_idle_connections Number of idle connections in the pool.
+# TYPE db_pool_idle_connections gauge
+db_pool_idle_connections 1
+`
+
+	// act
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"db_pool_acquired_connections", "db_pool_idle_connections",
+		"db_pool_empty_acquires_total", "db_pool_acquire_duration_seconds_total")
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 7, testutil.CollectAndCount(collector))
+}
+
+func TestArticleCollector(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var calls int
+	count := NewArticleCollector(func(context.Context) (int64, error) {
+		calls++
+		return 42, nil
+	})
+	failing := NewArticleCollector(func(context.Context) (int64, error) { return 0, errors.New("connection refused") })
+	uncounted := NewArticleCollector(func(context.Context) (int64, error) { return 42, nil })
+
+	// act
+	count.refresh(context.Background())
+	failing.refresh(context.Background())
+
+	// assert
+	assert.Equal(t, 42.0, testutil.ToFloat64(count))
+	assert.Equal(t, 42.0, testutil.ToFloat64(count))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 0, testutil.CollectAndCount(failing))
+	assert.Equal(t, 0, testutil.CollectAndCount(uncounted))
+}
+
+type fakeProducer struct {
+	err error
+}
+
+func (producer fakeProducer) SendEvent(string, kafka.Event) error {
+	return producer.err
+}
+
+func TestKafkaMetrics(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	metrics := NewKafkaMetrics(prometheus.NewRegistry())
+	healthy := metrics.Producer("relay", fakeProducer{})
+	failing := metrics.Producer("handlers", fakeProducer{err: errors.New("broker unavailable")})
+
+	// act
+	require.NoError(t, healthy.SendEvent("crud", kafka.Event{}))
+	require.Error(t, failing.SendEvent("crud", kafka.Event{}))
+	metrics.ObserveLag("crud", 2, 5)
+	metrics.ObserveLag("crud", 2, 3)
+	metrics.ObserveLag("crud", 3, 4)
+	metrics.ForgetLag("crud", 3)
+
+	// assert
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.sendLatency))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.sendErrors.WithLabelValues("relay", "crud")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.sendErrors.WithLabelValues("handlers", "crud")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.lag.WithLabelValues("crud", "2")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.lag))
+}
+
+func TestHandlerCollector(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	collector := NewHandlerCollector("events", func() map[string]kafka.HandlerStats {
+		return map[string]kafka.HandlerStats{"audit-log": {Processed: 5, Failed: 1, Retries: 3}}
+	})
+	expected := `
+# HELP kafka_handler_failures_total Number of events that still failed after their retries.
+# TYPE kafka_handler_failures_total counter
+kafka_handler_failures_total{consumer="events",handler="audit-log"} 1
+# HELP kafka_handler_processed_total Number of events handled successfully.
+# TYPE kafka_handler_processed_total counter
+kafka_handler_processed_total{consumer="events",handler="audit-log"} 5
+# HELP kafka_handler_retries_total Number of retried attempts to handle events.
+# TYPE kafka_handler_retries_total counter
+kafka_handler_retries_total{consumer="events",handler="audit-log"} 3
+`
+
+	// act
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected))
+
+	// assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/interceptors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RPCMetrics counts the RPCs served and observes their latency per method and
+// code.
+type RPCMetrics struct {
+	handled *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+}
+
+// NewRPCMetrics creates the RPC collectors and registers them, together with
+// the count of recovered panics, with registerer.
+func NewRPCMetrics(registerer prometheus.Registerer) *RPCMetrics {
+	metrics := &RPCMetrics{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Number of RPCs completed on the server.",
+		}, []string{"grpc_method", "grpc_code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Latency of the RPCs completed on the server.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_method", "grpc_code"}),
+	}
+	registerer.MustRegister(
+		metrics.handled,
+		metrics.latency,
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "grpc_server_panics_recovered_total",
+			Help: "Number of panics recovered from RPC handlers.",
+		}, func() float64 {
+			return float64(interceptors.PanicCount())
+		}),
+	)
+	return metrics
+}
+
+// UnaryInterceptor observes unary RPCs. It must run outside the recovery
+// interceptor, so recovered panics are counted as Internal.
+func (metrics *RPCMetrics) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.observe(info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+// StreamInterceptor observes streaming RPCs once the stream ends.
+func (metrics *RPCMetrics) StreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	metrics.observe(info.FullMethod, err, time.Since(start))
+	return err
+}
+
+func (metrics *RPCMetrics) observe(method string, err error, elapsed time.Duration) {
+	code := status.Code(err).String()
+	metrics.handled.WithLabelValues(method, code).Inc()
+	metrics.latency.WithLabelValues(method, code).Observe(elapsed.Seconds())
+}
@@ -0,0 +1,61 @@
+package purger
+
+import (
+	"context"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/pkg/logger"
+)
+
+const (
+	DefaultRetention = 30 * 24 * time.Hour
+	defaultInterval  = time.Hour
+)
+
+type Store interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Purger hard-deletes articles that have been soft-deleted for longer than
+// the retention.
+type Purger struct {
+	store       Store
+	retention   time.Duration
+	interval    time.Duration
+	currentTime func() time.Time
+}
+
+func New(store Store, retention time.Duration) *Purger {
+	return &Purger{
+		store:       store,
+		retention:   retention,
+		interval:    defaultInterval,
+		currentTime: time.Now,
+	}
+}
+
+// Run purges right away and then once per interval until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.Purge(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Errorf(ctx, "failed to purge deleted articles: %v", err)
+		} else if purged > 0 {
+			logger.Infof(ctx, "purged %d deleted articles", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge hard-deletes the articles whose retention has expired.
+func (p *Purger) Purge(ctx context.Context) (int64, error) {
+	return p.store.PurgeDeleted(ctx, p.currentTime().Add(-p.retention))
+}
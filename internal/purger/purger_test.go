@@ -0,0 +1,38 @@
+package purger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	before time.Time
+}
+
+func (store *fakeStore) PurgeDeleted(_ context.Context, before time.Time) (int64, error) {
+	store.before = before
+	return 3, nil
+}
+
+func TestPurger_Purge(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	store := &fakeStore{}
+	p := New(store, 24*time.Hour)
+	p.currentTime = func() time.Time {
+		return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	}
+
+	// act
+	purged, err := p.Purge(context.Background())
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.Equal(t, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), store.before)
+}
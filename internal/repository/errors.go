@@ -2,4 +2,11 @@ package repository
 
 import "errors"
 
-var ErrArticalNotFound = errors.New("article not found")
+var (
+	ErrArticalNotFound   = errors.New("article not found")
+	ErrBatchAborted      = errors.New("batch aborted because another item failed")
+	ErrVersionConflict   = errors.New("article version does not match the expected version")
+	ErrArticleDeleted    = errors.New("article is deleted")
+	ErrArticleNotDeleted = errors.New("article is not deleted")
+	ErrRevisionNotFound  = errors.New("article revision not found")
+)
@@ -0,0 +1,95 @@
+package repository
+
+import "time"
+
+type SortField int
+
+const (
+	SortByID SortField = iota
+	SortByRating
+	SortByCreatedAt
+	SortByName
+)
+
+type SortDirection int
+
+const (
+	SortAscending SortDirection = iota
+	SortDescending
+)
+
+// ArticleFilter narrows an article listing. Zero values disable a condition;
+// every enabled condition must hold for an article to be returned.
+type ArticleFilter struct {
+	MinRating     *int64
+	MaxRating     *int64
+	NamePrefix    string
+	NameContains  string
+	CreatedAfter  *time.Time // inclusive
+	CreatedBefore *time.Time // exclusive
+	// IncludeDeleted also lists soft-deleted articles.
+	IncludeDeleted bool
+}
+
+type ArticleSort struct {
+	Field     SortField
+	Direction SortDirection
+}
+
+// ListCursor is the position of the last article of the previous page. Only
+// ID and the column selected by ArticleSort.Field are compared, id breaks ties.
+type ListCursor struct {
+	ID        int64
+	Rating    int64
+	Name      string
+	CreatedAt time.Time
+}
+
+// ListParams describes a single keyset page of articles.
+type ListParams struct {
+	Filter ArticleFilter
+	Sort   ArticleSort
+	After  *ListCursor
+	Limit  int
+}
+
+func CursorOf(article Article) *ListCursor {
+	return &ListCursor{
+		ID:        article.ID,
+		Rating:    article.Rating,
+		Name:      article.Name,
+		CreatedAt: article.CreatedAt,
+	}
+}
+
+// SearchCursor is the position of the last result of the previous search page.
+// Results are ordered by descending rank, id breaks ties.
+type SearchCursor struct {
+	Rank float32
+	ID   int64
+}
+
+type SearchParams struct {
+	Query string
+	After *SearchCursor
+	Limit int
+}
+
+type SearchResult struct {
+	Article
+	Rank    float32 `db:"rank" json:"rank"`
+	Snippet string  `db:"snippet" json:"snippet"`
+}
+
+func SearchCursorOf(result SearchResult) *SearchCursor {
+	return &SearchCursor{Rank: result.Rank, ID: result.ID}
+}
+
+// HistoryParams describes a single page of the revisions of an article,
+// ordered from the newest version. BeforeVersion is the version of the last
+// revision of the previous page, zero starts from the newest one.
+type HistoryParams struct {
+	ArticleID     int64
+	BeforeVersion int64
+	Limit         int
+}
@@ -42,6 +42,51 @@ func (m *MockArticleInterface) EXPECT() *MockArticleInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BatchCreate mocks base method.
+func (m *MockArticleInterface) BatchCreate(ctx context.Context, articles []repository.Article, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreate", ctx, articles, mode)
+	ret0, _ := ret[0].([]repository.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreate indicates an expected call of BatchCreate.
+func (mr *MockArticleInterfaceMockRecorder) BatchCreate(ctx, articles, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreate", reflect.TypeOf((*MockArticleInterface)(nil).BatchCreate), ctx, articles, mode)
+}
+
+// BatchDelete mocks base method.
+func (m *MockArticleInterface) BatchDelete(ctx context.Context, ids []int64, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchDelete", ctx, ids, mode)
+	ret0, _ := ret[0].([]repository.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchDelete indicates an expected call of BatchDelete.
+func (mr *MockArticleInterfaceMockRecorder) BatchDelete(ctx, ids, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchDelete", reflect.TypeOf((*MockArticleInterface)(nil).BatchDelete), ctx, ids, mode)
+}
+
+// BatchUpdate mocks base method.
+func (m *MockArticleInterface) BatchUpdate(ctx context.Context, updates []repository.ArticleUpdate, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchUpdate", ctx, updates, mode)
+	ret0, _ := ret[0].([]repository.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchUpdate indicates an expected call of BatchUpdate.
+func (mr *MockArticleInterfaceMockRecorder) BatchUpdate(ctx, updates, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchUpdate", reflect.TypeOf((*MockArticleInterface)(nil).BatchUpdate), ctx, updates, mode)
+}
+
 // Create mocks base method.
 func (m *MockArticleInterface) Create(ctx context.Context, article repository.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -58,17 +103,17 @@ func (mr *MockArticleInterfaceMockRecorder) Create(ctx, article any) *gomock.Cal
 }
 
 // Delete mocks base method.
-func (m *MockArticleInterface) Delete(ctx context.Context, id int64) error {
+func (m *MockArticleInterface) Delete(ctx context.Context, id, expectedVersion int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockArticleInterfaceMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockArticleInterfaceMockRecorder) Delete(ctx, id, expectedVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleInterface)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArticleInterface)(nil).Delete), ctx, id, expectedVersion)
 }
 
 // GetByID mocks base method.
@@ -86,18 +131,121 @@ func (mr *MockArticleInterfaceMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockArticleInterface)(nil).GetByID), ctx, id)
 }
 
+// GetRevision mocks base method.
+func (m *MockArticleInterface) GetRevision(ctx context.Context, id, version int64) (repository.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id, version)
+	ret0, _ := ret[0].(repository.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockArticleInterfaceMockRecorder) GetRevision(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockArticleInterface)(nil).GetRevision), ctx, id, version)
+}
+
+// History mocks base method.
+func (m *MockArticleInterface) History(ctx context.Context, params repository.HistoryParams) ([]repository.ArticleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, params)
+	ret0, _ := ret[0].([]repository.ArticleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockArticleInterfaceMockRecorder) History(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockArticleInterface)(nil).History), ctx, params)
+}
+
+// Import mocks base method.
+func (m *MockArticleInterface) Import(ctx context.Context, articles []repository.Article) (repository.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, articles)
+	ret0, _ := ret[0].(repository.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockArticleInterfaceMockRecorder) Import(ctx, articles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockArticleInterface)(nil).Import), ctx, articles)
+}
+
+// List mocks base method.
+func (m *MockArticleInterface) List(ctx context.Context, params repository.ListParams) ([]repository.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]repository.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockArticleInterfaceMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArticleInterface)(nil).List), ctx, params)
+}
+
+// Purge mocks base method.
+func (m *MockArticleInterface) Purge(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockArticleInterfaceMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockArticleInterface)(nil).Purge), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockArticleInterface) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockArticleInterfaceMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockArticleInterface)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockArticleInterface) Search(ctx context.Context, params repository.SearchParams) ([]repository.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, params)
+	ret0, _ := ret[0].([]repository.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockArticleInterfaceMockRecorder) Search(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockArticleInterface)(nil).Search), ctx, params)
+}
+
 // Update mocks base method.
-func (m *MockArticleInterface) Update(ctx context.Context, article repository.Article) error {
+func (m *MockArticleInterface) Update(ctx context.Context, update repository.ArticleUpdate) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, article)
+	ret := m.ctrl.Call(m, "Update", ctx, update)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockArticleInterfaceMockRecorder) Update(ctx, article any) *gomock.Call {
+func (mr *MockArticleInterfaceMockRecorder) Update(ctx, update any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockArticleInterface)(nil).Update), ctx, article)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockArticleInterface)(nil).Update), ctx, update)
 }
 
 // MockDataBaseInterface is a mock of DataBaseInterface interface.
@@ -123,6 +271,36 @@ func (m *MockDataBaseInterface) EXPECT() *MockDataBaseInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BeginTx mocks base method.
+func (m *MockDataBaseInterface) BeginTx(ctx context.Context, options v5.TxOptions) (v5.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTx", ctx, options)
+	ret0, _ := ret[0].(v5.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTx indicates an expected call of BeginTx.
+func (mr *MockDataBaseInterfaceMockRecorder) BeginTx(ctx, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockDataBaseInterface)(nil).BeginTx), ctx, options)
+}
+
+// CopyFrom mocks base method.
+func (m *MockDataBaseInterface) CopyFrom(ctx context.Context, tableName v5.Identifier, columns []string, rows v5.CopyFromSource) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFrom", ctx, tableName, columns, rows)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFrom indicates an expected call of CopyFrom.
+func (mr *MockDataBaseInterfaceMockRecorder) CopyFrom(ctx, tableName, columns, rows any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFrom", reflect.TypeOf((*MockDataBaseInterface)(nil).CopyFrom), ctx, tableName, columns, rows)
+}
+
 // Exec mocks base method.
 func (m *MockDataBaseInterface) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
 	m.ctrl.T.Helper()
@@ -195,6 +373,20 @@ func (mr *MockDataBaseInterfaceMockRecorder) GetPool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockDataBaseInterface)(nil).GetPool))
 }
 
+// RunInTx mocks base method.
+func (m *MockDataBaseInterface) RunInTx(ctx context.Context, options v5.TxOptions, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInTx", ctx, options, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunInTx indicates an expected call of RunInTx.
+func (mr *MockDataBaseInterfaceMockRecorder) RunInTx(ctx, options, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInTx", reflect.TypeOf((*MockDataBaseInterface)(nil).RunInTx), ctx, options, fn)
+}
+
 // Select mocks base method.
 func (m *MockDataBaseInterface) Select(ctx context.Context, dest any, query string, args ...any) error {
 	m.ctrl.T.Helper()
@@ -213,3 +405,17 @@ func (mr *MockDataBaseInterfaceMockRecorder) Select(ctx, dest, query any, args .
 	varargs := append([]any{ctx, dest, query}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Select", reflect.TypeOf((*MockDataBaseInterface)(nil).Select), varargs...)
 }
+
+// SendBatch mocks base method.
+func (m *MockDataBaseInterface) SendBatch(ctx context.Context, batch *v5.Batch) v5.BatchResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendBatch", ctx, batch)
+	ret0, _ := ret[0].(v5.BatchResults)
+	return ret0
+}
+
+// SendBatch indicates an expected call of SendBatch.
+func (mr *MockDataBaseInterfaceMockRecorder) SendBatch(ctx, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBatch", reflect.TypeOf((*MockDataBaseInterface)(nil).SendBatch), ctx, batch)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "time"
+
+// Event types recorded in the outbox for article mutations.
+const (
+	EventArticleCreated  = "ArticleCreated"
+	EventArticleUpdated  = "ArticleUpdated"
+	EventArticleDeleted  = "ArticleDeleted"
+	EventArticleRestored = "ArticleRestored"
+	EventArticlePurged   = "ArticlePurged"
+)
+
+// OutboxRecord is an event written in the same transaction as the mutation it
+// describes. Payload is the JSON encoded Article after the change, or before
+// it for purges. Other changes carry the previous Article under "before", and
+// updates list the fields that got a different value under "changed_fields".
+type OutboxRecord struct {
+	ID          int64     `db:"id"`
+	AggregateID int64     `db:"aggregate_id"`
+	EventType   string    `db:"event_type"`
+	Payload     string    `db:"payload"`
+	CreatedAt   time.Time `db:"created_at"`
+	Attempts    int       `db:"attempts"`
+}
@@ -3,10 +3,14 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"time"
+
 	"github.com/NRKA/gRPC-Server/internal/repository"
 	"github.com/jackc/pgx/v5"
 )
 
+const revisionColumns = "article_id,version,name,rating,deleted,changed_fields,COALESCE(principal,'') AS principal,created_at"
+
 type ArticleRepo struct {
 	db repository.DataBaseInterface
 }
@@ -17,41 +21,137 @@ func NewArticleRepo(database repository.DataBaseInterface) *ArticleRepo {
 
 func (r *ArticleRepo) Create(ctx context.Context, article repository.Article) (int64, error) {
 	var id int64
-	err := r.db.ExecQueryRow(ctx, `INSERT INTO articles(name,rating) VALUES($1,$2) RETURNING id;`,
-		article.Name, article.Rating).Scan(&id)
+	err := r.db.ExecQueryRow(ctx, createArticleQuery, article.Name, article.Rating,
+		repository.PrincipalFromContext(ctx)).Scan(&id)
 	return id, err
 }
 
+// GetByID returns a live article. Soft-deleted articles are reported as
+// ErrArticleDeleted.
 func (r *ArticleRepo) GetByID(ctx context.Context, id int64) (repository.Article, error) {
 	var article repository.Article
-	err := r.db.Get(ctx, &article, "SELECT id,name,rating,created_at FROM articles WHERE id=$1", id)
+	err := r.db.Get(ctx, &article, "SELECT "+articleColumns+" FROM articles WHERE id=$1", id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return article, repository.ErrArticalNotFound
 		}
 		return article, err
 	}
+	if article.DeletedAt != nil {
+		return repository.Article{}, repository.ErrArticleDeleted
+	}
 	return article, nil
 }
-func (r *ArticleRepo) Delete(ctx context.Context, id int64) error {
-	commandTag, err := r.db.Exec(ctx, "DELETE FROM articles WHERE id=$1", id)
+
+// Delete soft-deletes a live article.
+func (r *ArticleRepo) Delete(ctx context.Context, id int64, expectedVersion int64) error {
+	return r.execChecked(ctx, false, deleteArticleQuery, id, expectedVersion, repository.PrincipalFromContext(ctx))
+}
+
+func (r *ArticleRepo) Update(ctx context.Context, update repository.ArticleUpdate) error {
+	query, args, err := buildUpdateQuery(update, repository.PrincipalFromContext(ctx))
 	if err != nil {
 		return err
 	}
-	if commandTag.RowsAffected() == 0 {
-		return repository.ErrArticalNotFound
-	}
-	return err
+	return r.execChecked(ctx, false, query, args...)
+}
+
+// Restore brings back a soft-deleted article.
+func (r *ArticleRepo) Restore(ctx context.Context, id int64) error {
+	return r.execChecked(ctx, true, restoreArticleQuery, id, repository.PrincipalFromContext(ctx))
 }
 
-func (r *ArticleRepo) Update(ctx context.Context, article repository.Article) error {
-	commandTag, err := r.db.Exec(ctx, "UPDATE articles SET name=$1, rating=$2 WHERE id=$3",
-		article.Name, article.Rating, article.ID)
+// Purge hard-deletes a soft-deleted article.
+func (r *ArticleRepo) Purge(ctx context.Context, id int64) error {
+	return r.execChecked(ctx, true, purgeArticleQuery, id)
+}
+
+// PurgeDeleted hard-deletes articles soft-deleted before the given time and
+// returns how many were removed.
+func (r *ArticleRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.ExecQueryRow(ctx, purgeDeletedQuery, before).Scan(&purged)
+	return purged, err
+}
+
+// Count returns the number of live articles.
+func (r *ArticleRepo) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.ExecQueryRow(ctx, "SELECT count(*) FROM articles WHERE deleted_at IS NULL").Scan(&count)
+	return count, err
+}
+
+// History returns a page of the revisions of an article, newest first. It
+// returns ErrArticalNotFound for an article that never existed; deleted and
+// purged articles keep their history.
+func (r *ArticleRepo) History(ctx context.Context, params repository.HistoryParams) ([]repository.ArticleRevision, error) {
+	revisions := make([]repository.ArticleRevision, 0, params.Limit)
+	err := r.db.Select(ctx, &revisions, "SELECT "+revisionColumns+" FROM article_revisions"+
+		" WHERE article_id=$1 AND ($2=0 OR version<$2) ORDER BY version DESC LIMIT $3",
+		params.ArticleID, params.BeforeVersion, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) > 0 {
+		return revisions, nil
+	}
+
+	var exists bool
+	err = r.db.ExecQueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM articles WHERE id=$1)"+
+		" OR EXISTS(SELECT 1 FROM article_revisions WHERE article_id=$1)", params.ArticleID).Scan(&exists)
 	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrArticalNotFound
+	}
+	return revisions, nil
+}
+
+// GetRevision returns the revision that produced the given version of an
+// article, which is also available once the article has been purged.
+func (r *ArticleRepo) GetRevision(ctx context.Context, id int64, version int64) (repository.ArticleRevision, error) {
+	var revision repository.ArticleRevision
+	err := r.db.Get(ctx, &revision, "SELECT "+revisionColumns+" FROM article_revisions WHERE article_id=$1 AND version=$2",
+		id, version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return revision, repository.ErrRevisionNotFound
+	}
+	return revision, err
+}
+
+// execChecked runs a single article mutation built with checkedResult.
+func (r *ArticleRepo) execChecked(ctx context.Context, wantDeleted bool, query string, args ...interface{}) error {
+	var changed bool
+	var deleted *bool
+	if err := r.db.ExecQueryRow(ctx, query, args...).Scan(&changed, &deleted); err != nil {
 		return err
 	}
-	if commandTag.RowsAffected() == 0 {
-		return repository.ErrArticalNotFound
+	return checkedError(changed, deleted, wantDeleted)
+}
+
+func (r *ArticleRepo) List(ctx context.Context, params repository.ListParams) ([]repository.Article, error) {
+	query, args, err := buildListQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	articles := make([]repository.Article, 0, params.Limit)
+	err = r.db.Select(ctx, &articles, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func (r *ArticleRepo) Search(ctx context.Context, params repository.SearchParams) ([]repository.SearchResult, error) {
+	query, args := buildSearchQuery(params)
+	results := make([]repository.SearchResult, 0, params.Limit)
+	err := r.db.Select(ctx, &results, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Snippet = highlightSnippet(results[i].Snippet)
 	}
-	return err
+	return results, nil
 }
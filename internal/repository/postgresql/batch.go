@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type batchSender interface {
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+}
+
+// batchItem is one statement of a batch. read consumes its result and returns
+// the affected article id.
+type batchItem struct {
+	query string
+	args  []interface{}
+	read  func(results pgx.BatchResults) (int64, error)
+}
+
+func (r *ArticleRepo) BatchCreate(ctx context.Context, articles []repository.Article, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	principal := repository.PrincipalFromContext(ctx)
+	items := make([]batchItem, 0, len(articles))
+	for _, article := range articles {
+		items = append(items, batchItem{
+			query: createArticleQuery,
+			args:  []interface{}{article.Name, article.Rating, principal},
+			read: func(results pgx.BatchResults) (int64, error) {
+				var id int64
+				err := results.QueryRow().Scan(&id)
+				return id, err
+			},
+		})
+	}
+	return r.runBatch(ctx, items, mode)
+}
+
+func (r *ArticleRepo) BatchUpdate(ctx context.Context, updates []repository.ArticleUpdate, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	principal := repository.PrincipalFromContext(ctx)
+	items := make([]batchItem, 0, len(updates))
+	for _, update := range updates {
+		query, args, err := buildUpdateQuery(update, principal)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batchItem{
+			query: query,
+			args:  args,
+			read:  readChecked(update.Article.ID),
+		})
+	}
+	return r.runBatch(ctx, items, mode)
+}
+
+func (r *ArticleRepo) BatchDelete(ctx context.Context, ids []int64, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	principal := repository.PrincipalFromContext(ctx)
+	items := make([]batchItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, batchItem{
+			query: deleteArticleQuery,
+			args:  []interface{}{id, 0, principal},
+			read:  readChecked(id),
+		})
+	}
+	return r.runBatch(ctx, items, mode)
+}
+
+func readChecked(id int64) func(results pgx.BatchResults) (int64, error) {
+	return func(results pgx.BatchResults) (int64, error) {
+		var changed bool
+		var deleted *bool
+		if err := results.QueryRow().Scan(&changed, &deleted); err != nil {
+			return id, err
+		}
+		return id, checkedError(changed, deleted, false)
+	}
+}
+
+func (r *ArticleRepo) runBatch(ctx context.Context, items []batchItem, mode repository.BatchMode) ([]repository.BatchItemResult, error) {
+	results := make([]repository.BatchItemResult, len(items))
+	if len(items) == 0 {
+		return results, nil
+	}
+	pending := make([]int, len(items))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	if mode == repository.BatchAtomic {
+		return results, r.runAtomicBatch(ctx, items, pending, results)
+	}
+
+	// A pipelined batch outside of a transaction runs as a single implicit
+	// transaction, so a failing statement rolls back the ones before it as well.
+	// Drop the failed item and replay the rest until the batch goes through.
+	for len(pending) > 0 {
+		failed, err := sendBatch(ctx, r.db, items, pending, results)
+		if err != nil {
+			return nil, err
+		}
+		if failed < 0 {
+			break
+		}
+		pending = append(pending[:failed:failed], pending[failed+1:]...)
+	}
+	return results, nil
+}
+
+func (r *ArticleRepo) runAtomicBatch(ctx context.Context, items []batchItem, pending []int, results []repository.BatchItemResult) error {
+	err := r.db.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		if _, err := sendBatch(ctx, r.db, items, pending, results); err != nil {
+			return err
+		}
+		for i := range results {
+			if results[i].Err == nil {
+				continue
+			}
+			for j := range results {
+				if j != i {
+					results[j].Err = repository.ErrBatchAborted
+				}
+			}
+			return repository.ErrBatchAborted
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrBatchAborted) {
+		return nil
+	}
+	return err
+}
+
+// sendBatch executes the pending items as one pipeline and records their
+// results. It returns the position in pending of the first statement rejected
+// by the server, after which the remaining items were not applied, or -1.
+// Items that matched no live article or failed their version check are
+// recorded without stopping the batch.
+func sendBatch(ctx context.Context, sender batchSender, items []batchItem, pending []int, results []repository.BatchItemResult) (int, error) {
+	batch := &pgx.Batch{}
+	for _, i := range pending {
+		batch.Queue(items[i].query, items[i].args...)
+	}
+	batchResults := sender.SendBatch(ctx, batch)
+
+	for position, i := range pending {
+		id, err := items[i].read(batchResults)
+		results[i] = repository.BatchItemResult{ID: id, Err: err}
+		if err == nil || errors.Is(err, repository.ErrArticalNotFound) || errors.Is(err, repository.ErrArticleDeleted) ||
+			errors.Is(err, repository.ErrVersionConflict) {
+			continue
+		}
+		batchResults.Close()
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			return position, nil
+		}
+		return -1, err
+	}
+	return -1, batchResults.Close()
+}
@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// createImportTableQuery creates the staging table of a chunk, which is dropped
+// with the transaction.
+const createImportTableQuery = "CREATE TEMPORARY TABLE import_articles(position BIGINT NOT NULL, name TEXT NOT NULL," +
+	" rating INT NOT NULL) ON COMMIT DROP"
+
+var importArticlesQuery = withRevision("INSERT INTO articles(name,rating)"+
+	" SELECT name,rating FROM import_articles ORDER BY position RETURNING "+articleColumns,
+	repository.EventArticleCreated, articlePayload, "ARRAY['name','rating']", "$1", "SELECT COUNT(*) FROM changed")
+
+// Import copies a chunk of articles with COPY, skipping duplicates of live
+// articles, so a name freed by a soft delete can be imported again. The check
+// for existing names and the copy run in one serializable transaction, so an
+// article created concurrently with the same name is detected on retry.
+func (r *ArticleRepo) Import(ctx context.Context, articles []repository.Article) (repository.ImportResult, error) {
+	var result repository.ImportResult
+	if len(articles) == 0 {
+		return result, nil
+	}
+	err := r.db.RunInTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}, func(ctx context.Context) error {
+		var err error
+		result, err = r.importChunk(ctx, articles)
+		return err
+	})
+	return result, err
+}
+
+func (r *ArticleRepo) importChunk(ctx context.Context, articles []repository.Article) (repository.ImportResult, error) {
+	var result repository.ImportResult
+
+	names := make([]string, 0, len(articles))
+	for _, article := range articles {
+		names = append(names, article.Name)
+	}
+	var existing []string
+	err := r.db.Select(ctx, &existing, "SELECT DISTINCT name FROM articles WHERE name=ANY($1) AND deleted_at IS NULL", names)
+	if err != nil {
+		return result, err
+	}
+
+	seen := make(map[string]struct{}, len(articles)+len(existing))
+	for _, name := range existing {
+		seen[name] = struct{}{}
+	}
+	rows := make([][]interface{}, 0, len(articles))
+	for _, article := range articles {
+		if _, ok := seen[article.Name]; ok {
+			result.Duplicates++
+			continue
+		}
+		seen[article.Name] = struct{}{}
+		rows = append(rows, []interface{}{int64(len(rows)), article.Name, article.Rating})
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	// COPY cannot return the generated ids, so the rows are copied into a
+	// staging table and moved into articles by a statement that writes their
+	// outbox events and first revisions as well.
+	if _, err = r.db.Exec(ctx, createImportTableQuery); err != nil {
+		return result, err
+	}
+	_, err = r.db.CopyFrom(ctx, pgx.Identifier{"import_articles"}, []string{"position", "name", "rating"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return result, err
+	}
+	err = r.db.ExecQueryRow(ctx, importArticlesQuery, repository.PrincipalFromContext(ctx)).Scan(&result.Inserted)
+	return result, err
+}
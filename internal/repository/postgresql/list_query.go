@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+)
+
+const articleColumns = "id,name,rating,created_at,version,deleted_at"
+
+// sortColumns is the only source of identifiers interpolated into listing
+// queries; every user supplied value goes through a placeholder.
+var sortColumns = map[repository.SortField]string{
+	repository.SortByID:        "id",
+	repository.SortByRating:    "rating",
+	repository.SortByCreatedAt: "created_at",
+	repository.SortByName:      "name",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) where(format string, values ...interface{}) {
+	placeholders := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, b.arg(value))
+	}
+	b.conditions = append(b.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+func (b *queryBuilder) filter(filter repository.ArticleFilter) {
+	if filter.MinRating != nil {
+		b.where("rating>=%s", *filter.MinRating)
+	}
+	if filter.MaxRating != nil {
+		b.where("rating<=%s", *filter.MaxRating)
+	}
+	if filter.NamePrefix != "" {
+		b.where("name LIKE %s", likeEscaper.Replace(filter.NamePrefix)+"%")
+	}
+	if filter.NameContains != "" {
+		b.where("name ILIKE %s", "%"+likeEscaper.Replace(filter.NameContains)+"%")
+	}
+	if filter.CreatedAfter != nil {
+		b.where("created_at>=%s", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		b.where("created_at<%s", *filter.CreatedBefore)
+	}
+	if !filter.IncludeDeleted {
+		b.where("deleted_at IS NULL")
+	}
+}
+
+func cursorValue(field repository.SortField, cursor *repository.ListCursor) interface{} {
+	switch field {
+	case repository.SortByRating:
+		return cursor.Rating
+	case repository.SortByCreatedAt:
+		return cursor.CreatedAt
+	case repository.SortByName:
+		return cursor.Name
+	}
+	return cursor.ID
+}
+
+func buildListQuery(params repository.ListParams) (string, []interface{}, error) {
+	column, ok := sortColumns[params.Sort.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown sort field %d", params.Sort.Field)
+	}
+	direction, comparison := "ASC", ">"
+	if params.Sort.Direction == repository.SortDescending {
+		direction, comparison = "DESC", "<"
+	}
+
+	b := &queryBuilder{}
+	b.filter(params.Filter)
+	if params.After != nil {
+		if params.Sort.Field == repository.SortByID {
+			b.where("id"+comparison+"%s", params.After.ID)
+		} else {
+			b.where("("+column+",id)"+comparison+"(%s,%s)", cursorValue(params.Sort.Field, params.After), params.After.ID)
+		}
+	}
+
+	order := "id " + direction
+	if params.Sort.Field != repository.SortByID {
+		order = column + " " + direction + "," + order
+	}
+	query := "SELECT " + articleColumns + " FROM articles" + b.whereClause() +
+		" ORDER BY " + order + " LIMIT " + b.arg(params.Limit)
+	return query, b.args, nil
+}
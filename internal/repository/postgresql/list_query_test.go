@@ -0,0 +1,99 @@
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildListQuery(t *testing.T) {
+	t.Parallel()
+
+	minRating, maxRating := int64(1), int64(9)
+	createdAfter := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	createdBefore := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		params        repository.ListParams
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{{
+		name:          "default order",
+		params:        repository.ListParams{Limit: 10},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at FROM articles WHERE deleted_at IS NULL ORDER BY id ASC LIMIT $1",
+		expectedArgs:  []interface{}{10},
+	}, {
+		name: "including deleted",
+		params: repository.ListParams{
+			Filter: repository.ArticleFilter{IncludeDeleted: true},
+			Limit:  10,
+		},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at FROM articles ORDER BY id ASC LIMIT $1",
+		expectedArgs:  []interface{}{10},
+	}, {
+		name: "id cursor descending",
+		params: repository.ListParams{
+			Sort:  repository.ArticleSort{Field: repository.SortByID, Direction: repository.SortDescending},
+			After: &repository.ListCursor{ID: 5},
+			Limit: 10,
+		},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at FROM articles WHERE deleted_at IS NULL AND id<$1 ORDER BY id DESC LIMIT $2",
+		expectedArgs:  []interface{}{int64(5), 10},
+	}, {
+		name: "all filters with rating cursor",
+		params: repository.ListParams{
+			Filter: repository.ArticleFilter{
+				MinRating:     &minRating,
+				MaxRating:     &maxRating,
+				NamePrefix:    "50%_",
+				NameContains:  `a\b`,
+				CreatedAfter:  &createdAfter,
+				CreatedBefore: &createdBefore,
+			},
+			Sort:  repository.ArticleSort{Field: repository.SortByRating},
+			After: &repository.ListCursor{ID: 5, Rating: 3},
+			Limit: 10,
+		},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at FROM articles WHERE rating>=$1 AND rating<=$2" +
+			" AND name LIKE $3 AND name ILIKE $4 AND created_at>=$5 AND created_at<$6 AND deleted_at IS NULL AND (rating,id)>($7,$8)" +
+			" ORDER BY rating ASC,id ASC LIMIT $9",
+		expectedArgs: []interface{}{minRating, maxRating, `50\%\_%`, `%a\\b%`, createdAfter, createdBefore,
+			int64(3), int64(5), 10},
+	}, {
+		name: "name cursor descending",
+		params: repository.ListParams{
+			Sort:  repository.ArticleSort{Field: repository.SortByName, Direction: repository.SortDescending},
+			After: &repository.ListCursor{ID: 5, Name: "name"},
+			Limit: 10,
+		},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at FROM articles WHERE deleted_at IS NULL AND (name,id)<($1,$2)" +
+			" ORDER BY name DESC,id DESC LIMIT $3",
+		expectedArgs: []interface{}{"name", int64(5), 10},
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, args, err := buildListQuery(tc.params)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedQuery, query)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestBuildListQuery_UnknownSortField(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := buildListQuery(repository.ListParams{Sort: repository.ArticleSort{Field: 42}})
+
+	assert.Error(t, err)
+}
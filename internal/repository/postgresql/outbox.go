@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// articlePayload is the outbox payload of a changed article row.
+var articlePayload = articleState("name", "rating", "version", "deleted_at")
+
+// articleState builds the JSON of an article from the changed row, with the
+// given expressions for the columns a mutation can change.
+func articleState(name, rating, version, deletedAt string) string {
+	return "jsonb_build_object('id',id,'name'," + name + ",'rating'," + rating + ",'created_at',created_at," +
+		"'version'," + version + ",'deleted_at'," + deletedAt + ")"
+}
+
+// withBefore adds the state of the article before the mutation to payload.
+func withBefore(payload, before string) string {
+	return payload + "||jsonb_build_object('before'," + before + ")"
+}
+
+// withOutbox wraps a single-row article mutation so that the outbox event is
+// inserted by the same statement and therefore commits or rolls back with it.
+// The mutation must return the article columns used by payload, and result is
+// the final SELECT, which can refer to the changed row as "changed".
+func withOutbox(mutation, eventType, payload, result string) string {
+	return "WITH changed AS (" + mutation + "), " + outboxEvent(eventType, payload) + " " + result
+}
+
+// withRevision is withOutbox for mutations that produce a new article version,
+// which is recorded in article_revisions by the same statement as well.
+// changedFields is a text[] expression over the changed row and principal the
+// placeholder of the acting principal.
+func withRevision(mutation, eventType, payload, changedFields, principal, result string) string {
+	return "WITH changed AS (" + mutation + "), " + outboxEvent(eventType, payload) + ", " +
+		"revision AS (INSERT INTO article_revisions(article_id,version,name,rating,deleted,changed_fields,principal)" +
+		" SELECT id,version,name,rating,deleted_at IS NOT NULL," + changedFields + ",NULLIF(" + principal + ",'')" +
+		" FROM changed) " + result
+}
+
+func outboxEvent(eventType, payload string) string {
+	return "event AS (INSERT INTO outbox(aggregate_id,event_type,payload) SELECT id,'" + eventType + "'," +
+		payload + " FROM changed)"
+}
+
+// checkedResult reports whether a row was changed and, when the article
+// existed, whether it was soft-deleted, which tells why a mutation did not
+// apply. Both subqueries see the table as it was before the mutation.
+func checkedResult(idParam string) string {
+	return "SELECT EXISTS(SELECT 1 FROM changed),(SELECT deleted_at IS NOT NULL FROM articles WHERE id=" + idParam + ")"
+}
+
+// Purges leave the revisions of the article in place as its audit trail.
+var (
+	createArticleQuery = withRevision("INSERT INTO articles(name,rating) VALUES($1,$2) RETURNING "+articleColumns,
+		repository.EventArticleCreated, articlePayload, "ARRAY['name','rating']", "$3", "SELECT id FROM changed")
+	deleteArticleQuery = withRevision("UPDATE articles SET deleted_at=NOW(),version=version+1"+
+		" WHERE id=$1 AND deleted_at IS NULL AND ($2=0 OR version=$2) RETURNING "+articleColumns,
+		repository.EventArticleDeleted, withBefore(articlePayload, articleState("name", "rating", "version-1", "NULL")),
+		"ARRAY['deleted_at']", "$3", checkedResult("$1"))
+	restoreArticleQuery = withRevision("UPDATE articles SET deleted_at=NULL,version=version+1"+
+		" FROM (SELECT id AS old_id,deleted_at AS old_deleted_at FROM articles WHERE id=$1 FOR UPDATE) old"+
+		" WHERE id=old_id AND old_deleted_at IS NOT NULL RETURNING "+articleColumns+",old_deleted_at",
+		repository.EventArticleRestored, withBefore(articlePayload, articleState("name", "rating", "version-1", "old_deleted_at")),
+		"ARRAY['deleted_at']", "$2", checkedResult("$1"))
+	purgeArticleQuery = withOutbox("DELETE FROM articles WHERE id=$1 AND deleted_at IS NOT NULL RETURNING "+articleColumns,
+		repository.EventArticlePurged, articlePayload, checkedResult("$1"))
+	purgeDeletedQuery = withOutbox("DELETE FROM articles WHERE deleted_at<$1 RETURNING "+articleColumns,
+		repository.EventArticlePurged, articlePayload, "SELECT COUNT(*) FROM changed")
+)
+
+// checkedError converts the result of checkedResult into an error for a
+// mutation that applies to articles whose deletion state is wantDeleted.
+func checkedError(changed bool, deleted *bool, wantDeleted bool) error {
+	switch {
+	case changed:
+		return nil
+	case deleted == nil:
+		return repository.ErrArticalNotFound
+	case *deleted && !wantDeleted:
+		return repository.ErrArticleDeleted
+	case !*deleted && wantDeleted:
+		return repository.ErrArticleNotDeleted
+	}
+	return repository.ErrVersionConflict
+}
+
+// outboxLease is how long claimed events are left to the relay that claimed
+// them. Events it neither delivered nor failed by then, because it crashed or
+// lost the database, are claimed again.
+const outboxLease = time.Minute
+
+// claimOutboxQuery leases due events that are the oldest undelivered event of
+// their article, so an event is never published before its predecessors.
+// Events leased by another relay are skipped, and since they stay undelivered
+// they keep blocking the events queued behind them.
+const claimOutboxQuery = `UPDATE outbox SET claimed_until=NOW()+$2*interval '1 second' WHERE id IN (
+SELECT id FROM outbox o
+WHERE delivered_at IS NULL AND next_attempt_at<=NOW() AND (claimed_until IS NULL OR claimed_until<NOW())
+AND NOT EXISTS (SELECT 1 FROM outbox p WHERE p.aggregate_id=o.aggregate_id AND p.delivered_at IS NULL AND p.id<o.id)
+ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED)
+RETURNING id,aggregate_id,event_type,payload::text AS payload,created_at,attempts`
+
+type OutboxRepo struct {
+	db repository.DataBaseInterface
+}
+
+func NewOutboxRepo(database repository.DataBaseInterface) *OutboxRepo {
+	return &OutboxRepo{db: database}
+}
+
+// Relay leases up to limit due events, hands each to publish and records the
+// outcome. No transaction is open while publish runs, so it may take as long
+// as a broker round-trip does. Failed events are retried with exponential
+// backoff capped at five minutes. It returns how many events were published.
+func (r *OutboxRepo) Relay(ctx context.Context, limit int, publish func(record repository.OutboxRecord) error) (int, error) {
+	var records []repository.OutboxRecord
+	if err := r.db.Select(ctx, &records, claimOutboxQuery, limit, outboxLease.Seconds()); err != nil {
+		return 0, err
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	var delivered []int64
+	failed := make(map[int64]error)
+	for _, record := range records {
+		if err := publish(record); err != nil {
+			failed[record.ID] = err
+			continue
+		}
+		delivered = append(delivered, record.ID)
+	}
+
+	// The outcome of events that were published is recorded even when ctx is
+	// done, so they are not published again after the lease.
+	ctx = context.WithoutCancel(ctx)
+	err := r.db.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+		if len(delivered) > 0 {
+			_, err := r.db.Exec(ctx, "UPDATE outbox SET delivered_at=NOW(),claimed_until=NULL WHERE id=ANY($1)", delivered)
+			if err != nil {
+				return err
+			}
+		}
+		for id, publishErr := range failed {
+			_, err := r.db.Exec(ctx, `UPDATE outbox SET attempts=attempts+1, last_error=$1, claimed_until=NULL,
+next_attempt_at=NOW()+LEAST(power(2,attempts)*interval '1 second', interval '5 minutes') WHERE id=$2`,
+				publishErr.Error(), id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(delivered), nil
+}
+
+// PurgeDelivered deletes events delivered before the given time.
+func (r *OutboxRepo) PurgeDelivered(ctx context.Context, before time.Time) (int64, error) {
+	commandTag, err := r.db.Exec(ctx, "DELETE FROM outbox WHERE delivered_at<$1", before)
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
@@ -4,13 +4,25 @@ package postgresql
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/NRKA/gRPC-Server/internal/db/postgres"
 	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
+func fullUpdate(article repository.Article) repository.ArticleUpdate {
+	return repository.ArticleUpdate{
+		Article: article,
+		Fields:  []repository.ArticleField{repository.FieldName, repository.FieldRating},
+	}
+}
+
 func TestCreateArticle(t *testing.T) {
 	dbConnection := postgres.NewFromEnv()
 	defer dbConnection.DB.GetPool().Close()
@@ -150,7 +162,7 @@ func TestUpdateArticle(t *testing.T) {
 
 			if tc.expectedErr != nil {
 				//act
-				err := repo.Update(ctx, tc.article)
+				err := repo.Update(ctx, fullUpdate(tc.article))
 
 				//assert
 				assert.ErrorIs(t, err, tc.expectedErr)
@@ -163,7 +175,7 @@ func TestUpdateArticle(t *testing.T) {
 
 			//act
 			tc.article.ID = respCreate
-			err = repo.Update(ctx, tc.article)
+			err = repo.Update(ctx, fullUpdate(tc.article))
 			require.NoError(t, err)
 
 			//assert
@@ -204,7 +216,7 @@ func TestDeleteArticle(t *testing.T) {
 			repo := NewArticleRepo(dbConnection.DB)
 
 			if tc.expectedErr != nil {
-				err := repo.Delete(ctx, id)
+				err := repo.Delete(ctx, id, 0)
 
 				//assert
 				assert.ErrorIs(t, err, tc.expectedErr)
@@ -219,13 +231,720 @@ func TestDeleteArticle(t *testing.T) {
 			assert.NotZero(t, respCreate)
 
 			//act
-			err = repo.Delete(ctx, respCreate)
+			err = repo.Delete(ctx, respCreate, 0)
 			assert.ErrorIs(t, err, tc.expectedErr)
 
 			//assert
 			_, err = repo.GetByID(ctx, respCreate)
 
-			assert.ErrorIs(t, err, repository.ErrArticalNotFound)
+			assert.ErrorIs(t, err, repository.ErrArticleDeleted)
+		})
+	}
+}
+
+func TestListArticles(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	minRating := int64(20)
+	articles := []repository.Article{
+		{Name: "Gopher", Rating: 30},
+		{Name: "gopher 100%", Rating: 10},
+		{Name: "Rust", Rating: 20},
+		{Name: "Go", Rating: 20},
+	}
+	testCases := []struct {
+		name          string
+		params        func(ids []int64) repository.ListParams
+		expectedIndex []int
+	}{{
+		name: "first page",
+		params: func(ids []int64) repository.ListParams {
+			return repository.ListParams{Limit: 2}
+		},
+		expectedIndex: []int{0, 1},
+	}, {
+		name: "page after cursor",
+		params: func(ids []int64) repository.ListParams {
+			return repository.ListParams{After: &repository.ListCursor{ID: ids[1]}, Limit: 5}
+		},
+		expectedIndex: []int{2, 3},
+	}, {
+		name: "rating descending with ties broken by id",
+		params: func(ids []int64) repository.ListParams {
+			return repository.ListParams{
+				Sort:  repository.ArticleSort{Field: repository.SortByRating, Direction: repository.SortDescending},
+				Limit: 5,
+			}
+		},
+		expectedIndex: []int{0, 3, 2, 1},
+	}, {
+		name: "rating cursor inside a tie",
+		params: func(ids []int64) repository.ListParams {
+			return repository.ListParams{
+				Sort:  repository.ArticleSort{Field: repository.SortByRating, Direction: repository.SortDescending},
+				After: &repository.ListCursor{ID: ids[3], Rating: 20},
+				Limit: 5,
+			}
+		},
+		expectedIndex: []int{2, 1},
+	}, {
+		name: "name contains is case insensitive and literal",
+		params: func(ids []int64) repository.ListParams {
+			return repository.ListParams{Filter: repository.ArticleFilter{NameContains: "PHER 100%"}, Limit: 5}
+		},
+		expectedIndex: []int{1},
+	}, {
+		name: "name prefix and min rating sorted by name",
+		params: func(ids []int64) repository.ListParams {
+			return repository.ListParams{
+				Filter: repository.ArticleFilter{NamePrefix: "G", MinRating: &minRating},
+				Sort:   repository.ArticleSort{Field: repository.SortByName},
+				Limit:  5,
+			}
+		},
+		expectedIndex: []int{3, 0},
+	},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dbConnection.SetUp(t)
+			defer dbConnection.TearDown()
+
+			//arrange
+			repo := NewArticleRepo(dbConnection.DB)
+			ids := make([]int64, 0, len(articles))
+			for _, article := range articles {
+				id, err := repo.Create(ctx, article)
+				require.NoError(t, err)
+				ids = append(ids, id)
+			}
+
+			//act
+			listed, err := repo.List(ctx, tc.params(ids))
+
+			//assert
+			require.NoError(t, err)
+			expected := make([]int64, 0, len(tc.expectedIndex))
+			for _, i := range tc.expectedIndex {
+				expected = append(expected, ids[i])
+			}
+			actual := make([]int64, 0, len(listed))
+			for _, article := range listed {
+				actual = append(actual, article.ID)
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestSearchArticles(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	articles := []repository.Article{
+		{Name: "Concurrency in Go", Rating: 10},
+		{Name: "Go channels and go routines", Rating: 10},
+		{Name: "Rust ownership", Rating: 10},
+		{Name: "Rust <b>macros</b>", Rating: 10},
+	}
+	testCases := []struct {
+		name            string
+		params          func(ids []int64) repository.SearchParams
+		expectedIndex   []int
+		expectedSnippet string
+	}{{
+		name: "ranked by relevance",
+		params: func(ids []int64) repository.SearchParams {
+			return repository.SearchParams{Query: "go", Limit: 5}
+		},
+		expectedIndex:   []int{1, 0},
+		expectedSnippet: "<mark>Go</mark> channels and <mark>go</mark> routines",
+	}, {
+		name: "no matches",
+		params: func(ids []int64) repository.SearchParams {
+			return repository.SearchParams{Query: "python", Limit: 5}
+		},
+		expectedIndex: []int{},
+	}, {
+		name: "excluded words",
+		params: func(ids []int64) repository.SearchParams {
+			return repository.SearchParams{Query: "go -channels", Limit: 5}
+		},
+		expectedIndex:   []int{0},
+		expectedSnippet: "Concurrency in <mark>Go</mark>",
+	}, {
+		name: "escaped names",
+		params: func(ids []int64) repository.SearchParams {
+			return repository.SearchParams{Query: "macros", Limit: 5}
+		},
+		expectedIndex:   []int{3},
+		expectedSnippet: "Rust &lt;b&gt;<mark>macros</mark>&lt;/b&gt;",
+	},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dbConnection.SetUp(t)
+			defer dbConnection.TearDown()
+
+			//arrange
+			repo := NewArticleRepo(dbConnection.DB)
+			ids := make([]int64, 0, len(articles))
+			for _, article := range articles {
+				id, err := repo.Create(ctx, article)
+				require.NoError(t, err)
+				ids = append(ids, id)
+			}
+
+			//act
+			results, err := repo.Search(ctx, tc.params(ids))
+
+			//assert
+			require.NoError(t, err)
+			expected := make([]int64, 0, len(tc.expectedIndex))
+			for _, i := range tc.expectedIndex {
+				expected = append(expected, ids[i])
+			}
+			actual := make([]int64, 0, len(results))
+			for _, result := range results {
+				actual = append(actual, result.ID)
+			}
+			assert.Equal(t, expected, actual)
+			if len(results) > 0 {
+				assert.Equal(t, tc.expectedSnippet, results[0].Snippet)
+			}
+		})
+	}
+}
+
+func TestBatchArticles(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	missingID := int64(123123)
+
+	t.Run("atomic create", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+		repo := NewArticleRepo(dbConnection.DB)
+
+		//act
+		results, err := repo.BatchCreate(ctx, []repository.Article{
+			{Name: "first", Rating: 1},
+			{Name: "second", Rating: 2},
+		}, repository.BatchAtomic)
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.NoError(t, result.Err)
+			_, err = repo.GetByID(ctx, result.ID)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("atomic update rolls back on missing article", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+		repo := NewArticleRepo(dbConnection.DB)
+		id, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 22})
+		require.NoError(t, err)
+
+		//act
+		results, err := repo.BatchUpdate(ctx, []repository.ArticleUpdate{
+			fullUpdate(repository.Article{ID: id, Name: "NewName", Rating: 1}),
+			fullUpdate(repository.Article{ID: missingID, Name: "NewName", Rating: 1}),
+		}, repository.BatchAtomic)
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, repository.ErrBatchAborted)
+		assert.ErrorIs(t, results[1].Err, repository.ErrArticalNotFound)
+		article, err := repo.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Name", article.Name)
+	})
+
+	t.Run("best effort delete keeps going", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+		repo := NewArticleRepo(dbConnection.DB)
+		id, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 22})
+		require.NoError(t, err)
+
+		//act
+		results, err := repo.BatchDelete(ctx, []int64{missingID, id}, repository.BatchBestEffort)
+
+		//assert
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, repository.ErrArticalNotFound)
+		assert.NoError(t, results[1].Err)
+		_, err = repo.GetByID(ctx, id)
+		assert.ErrorIs(t, err, repository.ErrArticalNotFound)
+	})
+}
+
+func TestImportArticles(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	dbConnection.SetUp(t)
+	defer dbConnection.TearDown()
+
+	//arrange
+	repo := NewArticleRepo(dbConnection.DB)
+	_, err := repo.Create(ctx, repository.Article{Name: "existing", Rating: 1})
+	require.NoError(t, err)
+	deleted, err := repo.Create(ctx, repository.Article{Name: "deleted", Rating: 1})
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, deleted, 0))
+
+	//act
+	result, err := repo.Import(repository.WithPrincipal(ctx, "importer"), []repository.Article{
+		{Name: "existing", Rating: 2},
+		{Name: "deleted", Rating: 6},
+		{Name: "new", Rating: 3},
+		{Name: "new", Rating: 4},
+		{Name: "other", Rating: 5},
+	})
+
+	//assert
+	require.NoError(t, err)
+	assert.Equal(t, repository.ImportResult{Inserted: 3, Duplicates: 2}, result)
+	articles, err := repo.List(ctx, repository.ListParams{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, articles, 4)
+	var created []int64
+	for {
+		count, err := NewOutboxRepo(dbConnection.DB).Relay(ctx, 10, func(record repository.OutboxRecord) error {
+			if record.EventType == repository.EventArticleCreated && record.AggregateID != deleted {
+				created = append(created, record.AggregateID)
+			}
+			return nil
 		})
+		require.NoError(t, err)
+		if count == 0 {
+			break
+		}
 	}
+	assert.Len(t, created, 4, "the existing article and the imported ones")
+	revision, err := repo.GetRevision(ctx, created[len(created)-1], 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "rating"}, revision.ChangedFields)
+	assert.Equal(t, "importer", revision.Principal)
+}
+
+func TestOutbox(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+
+	t.Run("mutations are relayed in order per article", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		outbox := NewOutboxRepo(dbConnection.DB)
+		first, err := repo.Create(ctx, repository.Article{Name: "first", Rating: 1})
+		require.NoError(t, err)
+		require.NoError(t, repo.Update(ctx, fullUpdate(repository.Article{ID: first, Name: "renamed", Rating: 2})))
+		second, err := repo.Create(ctx, repository.Article{Name: "second", Rating: 3})
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, first, 0))
+
+		//act
+		var published []repository.OutboxRecord
+		for {
+			count, err := outbox.Relay(ctx, 10, func(record repository.OutboxRecord) error {
+				published = append(published, record)
+				return nil
+			})
+			require.NoError(t, err)
+			if count == 0 {
+				break
+			}
+		}
+
+		//assert
+		require.Len(t, published, 4)
+		var firstEvents []string
+		for _, record := range published {
+			if record.AggregateID == first {
+				firstEvents = append(firstEvents, record.EventType)
+			}
+		}
+		assert.Equal(t, []string{
+			repository.EventArticleCreated,
+			repository.EventArticleUpdated,
+			repository.EventArticleDeleted,
+		}, firstEvents)
+		assert.Contains(t, published[1].Payload, `"id": `)
+		assert.Equal(t, second, published[1].AggregateID)
+	})
+
+	t.Run("events carry the previous state", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		outbox := NewOutboxRepo(dbConnection.DB)
+		id, err := repo.Create(ctx, repository.Article{Name: "first", Rating: 1})
+		require.NoError(t, err)
+		require.NoError(t, repo.Update(ctx, fullUpdate(repository.Article{ID: id, Name: "renamed", Rating: 1})))
+		require.NoError(t, repo.Delete(ctx, id, 0))
+		require.NoError(t, repo.Restore(ctx, id))
+
+		//act
+		type payload struct {
+			repository.Article
+			Before        *repository.Article `json:"before"`
+			ChangedFields []string            `json:"changed_fields"`
+		}
+		var payloads []payload
+		_, err = outbox.Relay(ctx, 10, func(record repository.OutboxRecord) error {
+			var p payload
+			if err := json.Unmarshal([]byte(record.Payload), &p); err != nil {
+				return err
+			}
+			payloads = append(payloads, p)
+			return nil
+		})
+		require.NoError(t, err)
+
+		//assert
+		require.Len(t, payloads, 4)
+		assert.Nil(t, payloads[0].Before)
+		require.NotNil(t, payloads[1].Before)
+		assert.Equal(t, "first", payloads[1].Before.Name)
+		assert.Equal(t, int64(1), payloads[1].Before.Version)
+		assert.Equal(t, []string{"name"}, payloads[1].ChangedFields)
+		require.NotNil(t, payloads[2].Before)
+		assert.Nil(t, payloads[2].Before.DeletedAt)
+		assert.NotNil(t, payloads[2].DeletedAt)
+		require.NotNil(t, payloads[3].Before)
+		assert.Equal(t, payloads[2].DeletedAt, payloads[3].Before.DeletedAt)
+		assert.Nil(t, payloads[3].DeletedAt)
+	})
+
+	t.Run("failed mutation writes no event", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		outbox := NewOutboxRepo(dbConnection.DB)
+
+		//act
+		err := repo.Update(ctx, fullUpdate(repository.Article{ID: 123123, Name: "Name", Rating: 1}))
+
+		//assert
+		assert.ErrorIs(t, err, repository.ErrArticalNotFound)
+		count, err := outbox.Relay(ctx, 10, func(repository.OutboxRecord) error { return nil })
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("failed publish is retried later", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		outbox := NewOutboxRepo(dbConnection.DB)
+		_, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+		require.NoError(t, err)
+
+		//act
+		count, err := outbox.Relay(ctx, 10, func(repository.OutboxRecord) error {
+			return assert.AnError
+		})
+		require.NoError(t, err)
+		retried, err := outbox.Relay(ctx, 10, func(repository.OutboxRecord) error { return nil })
+
+		//assert
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Zero(t, retried, "event must wait for its backoff")
+		_, err = dbConnection.DB.Exec(ctx, "UPDATE outbox SET next_attempt_at=NOW()")
+		require.NoError(t, err)
+		retried, err = outbox.Relay(ctx, 10, func(repository.OutboxRecord) error { return nil })
+		require.NoError(t, err)
+		assert.Equal(t, 1, retried)
+	})
+
+	t.Run("leased events are skipped by other relays", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		outbox := NewOutboxRepo(dbConnection.DB)
+		_, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+		require.NoError(t, err)
+
+		//act
+		var concurrent int
+		count, err := outbox.Relay(ctx, 10, func(repository.OutboxRecord) error {
+			concurrent, err = outbox.Relay(ctx, 10, func(repository.OutboxRecord) error { return nil })
+			require.NoError(t, err)
+			return nil
+		})
+
+		//assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Zero(t, concurrent)
+	})
+
+	t.Run("expired leases are claimed again", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		outbox := NewOutboxRepo(dbConnection.DB)
+		_, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+		require.NoError(t, err)
+		_, err = dbConnection.DB.Exec(ctx, "UPDATE outbox SET claimed_until=NOW()-interval '1 second'")
+		require.NoError(t, err)
+
+		//act
+		count, err := outbox.Relay(ctx, 10, func(repository.OutboxRecord) error { return nil })
+
+		//assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestRunInTx(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+
+	t.Run("rolls back repository calls", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		errFailed := errors.New("failed")
+		var id int64
+
+		//act
+		err := dbConnection.DB.RunInTx(ctx, pgx.TxOptions{}, func(ctx context.Context) error {
+			var err error
+			id, err = repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+			require.NoError(t, err)
+			_, err = repo.GetByID(ctx, id)
+			require.NoError(t, err)
+			return errFailed
+		})
+
+		//assert
+		assert.ErrorIs(t, err, errFailed)
+		_, err = repo.GetByID(ctx, id)
+		assert.ErrorIs(t, err, repository.ErrArticalNotFound)
+	})
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		dbConnection.SetUp(t)
+		defer dbConnection.TearDown()
+
+		//arrange
+		repo := NewArticleRepo(dbConnection.DB)
+		attempts := 0
+
+		//act
+		err := dbConnection.DB.RunInTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}, func(ctx context.Context) error {
+			attempts++
+			if _, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1}); err != nil {
+				return err
+			}
+			if attempts == 1 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+
+		//assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		articles, err := repo.List(ctx, repository.ListParams{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, articles, 1)
+	})
+}
+
+func TestArticleVersion(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	dbConnection.SetUp(t)
+	defer dbConnection.TearDown()
+
+	//arrange
+	repo := NewArticleRepo(dbConnection.DB)
+	id, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+	require.NoError(t, err)
+
+	//act
+	err = repo.Update(ctx, fullUpdate(repository.Article{ID: id, Name: "First", Rating: 2, Version: 1}))
+	require.NoError(t, err)
+	staleUpdate := repo.Update(ctx, fullUpdate(repository.Article{ID: id, Name: "Second", Rating: 3, Version: 1}))
+	staleDelete := repo.Delete(ctx, id, 1)
+	missing := repo.Update(ctx, fullUpdate(repository.Article{ID: 123123, Name: "Name", Rating: 1, Version: 1}))
+
+	//assert
+	assert.ErrorIs(t, staleUpdate, repository.ErrVersionConflict)
+	assert.ErrorIs(t, staleDelete, repository.ErrVersionConflict)
+	assert.ErrorIs(t, missing, repository.ErrArticalNotFound)
+	article, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "First", article.Name)
+	assert.Equal(t, int64(2), article.Version)
+	assert.NoError(t, repo.Delete(ctx, id, 2))
+}
+
+func TestPartialUpdate(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	dbConnection.SetUp(t)
+	defer dbConnection.TearDown()
+
+	//arrange
+	repo := NewArticleRepo(dbConnection.DB)
+	id, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+	require.NoError(t, err)
+
+	//act
+	err = repo.Update(ctx, repository.ArticleUpdate{
+		Article: repository.Article{ID: id, Rating: 5},
+		Fields:  []repository.ArticleField{repository.FieldRating},
+	})
+
+	//assert
+	require.NoError(t, err)
+	article, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Name", article.Name)
+	assert.Equal(t, int64(5), article.Rating)
+	var changedFields []string
+	err = dbConnection.DB.Select(ctx, &changedFields,
+		"SELECT jsonb_array_elements_text(payload->'changed_fields') FROM outbox WHERE event_type=$1",
+		repository.EventArticleUpdated)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rating"}, changedFields)
+}
+
+func TestSoftDelete(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := context.Background()
+	dbConnection.SetUp(t)
+	defer dbConnection.TearDown()
+
+	//arrange
+	repo := NewArticleRepo(dbConnection.DB)
+	id, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+	require.NoError(t, err)
+
+	//act
+	liveRestore := repo.Restore(ctx, id)
+	livePurge := repo.Purge(ctx, id)
+	require.NoError(t, repo.Delete(ctx, id, 0))
+	_, getErr := repo.GetByID(ctx, id)
+	updateErr := repo.Update(ctx, fullUpdate(repository.Article{ID: id, Name: "Other", Rating: 2}))
+	deleteErr := repo.Delete(ctx, id, 0)
+	visible, err := repo.List(ctx, repository.ListParams{Limit: 10})
+	require.NoError(t, err)
+	all, err := repo.List(ctx, repository.ListParams{Filter: repository.ArticleFilter{IncludeDeleted: true}, Limit: 10})
+	require.NoError(t, err)
+
+	//assert
+	assert.ErrorIs(t, liveRestore, repository.ErrArticleNotDeleted)
+	assert.ErrorIs(t, livePurge, repository.ErrArticleNotDeleted)
+	assert.ErrorIs(t, getErr, repository.ErrArticleDeleted)
+	assert.ErrorIs(t, updateErr, repository.ErrArticleDeleted)
+	assert.ErrorIs(t, deleteErr, repository.ErrArticleDeleted)
+	assert.Empty(t, visible)
+	require.Len(t, all, 1)
+	assert.NotNil(t, all[0].DeletedAt)
+
+	require.NoError(t, repo.Restore(ctx, id))
+	article, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Nil(t, article.DeletedAt)
+	assert.Equal(t, int64(3), article.Version)
+
+	require.NoError(t, repo.Delete(ctx, id, 0))
+	purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	purged, err = repo.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.ErrorIs(t, repo.Purge(ctx, id), repository.ErrArticalNotFound)
+}
+
+func TestArticleHistory(t *testing.T) {
+	dbConnection := postgres.NewFromEnv()
+	defer dbConnection.DB.GetPool().Close()
+
+	ctx := repository.WithPrincipal(context.Background(), "alice")
+	dbConnection.SetUp(t)
+	defer dbConnection.TearDown()
+
+	//arrange
+	repo := NewArticleRepo(dbConnection.DB)
+	id, err := repo.Create(ctx, repository.Article{Name: "Name", Rating: 1})
+	require.NoError(t, err)
+	require.NoError(t, repo.Update(context.Background(), repository.ArticleUpdate{
+		Article: repository.Article{ID: id, Name: "Name", Rating: 2},
+		Fields:  []repository.ArticleField{repository.FieldName, repository.FieldRating},
+	}))
+	require.NoError(t, repo.Delete(ctx, id, 0))
+
+	//act
+	require.NoError(t, repo.Purge(ctx, id))
+	history, err := repo.History(ctx, repository.HistoryParams{ArticleID: id, Limit: 10})
+	require.NoError(t, err)
+	page, err := repo.History(ctx, repository.HistoryParams{ArticleID: id, BeforeVersion: 3, Limit: 1})
+	require.NoError(t, err)
+	created, err := repo.GetRevision(ctx, id, 1)
+	require.NoError(t, err)
+	_, missing := repo.GetRevision(ctx, id, 4)
+	_, unknown := repo.History(ctx, repository.HistoryParams{ArticleID: id + 1, Limit: 10})
+
+	//assert
+	require.Len(t, history, 3)
+	assert.Equal(t, []int64{3, 2, 1}, []int64{history[0].Version, history[1].Version, history[2].Version})
+	assert.True(t, history[0].Deleted)
+	assert.Equal(t, []string{"deleted_at"}, history[0].ChangedFields)
+	assert.Equal(t, []string{"rating"}, history[1].ChangedFields)
+	assert.Empty(t, history[1].Principal)
+	require.Len(t, page, 1)
+	assert.Equal(t, int64(2), page[0].Version)
+	assert.Equal(t, "Name", created.Name)
+	assert.Equal(t, []string{"name", "rating"}, created.ChangedFields)
+	assert.Equal(t, "alice", created.Principal)
+	assert.ErrorIs(t, missing, repository.ErrRevisionNotFound)
+	assert.ErrorIs(t, unknown, repository.ErrArticalNotFound)
 }
@@ -0,0 +1,43 @@
+package postgresql
+
+import (
+	"html"
+	"strings"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+)
+
+// searchConfig must match the configuration of the generated name_tsv column.
+const searchConfig = "'simple'"
+
+// ts_headline marks the matches with private-use characters rather than with
+// HTML, so the name can be escaped before the marks become <mark> tags. They
+// are removed from the name beforehand, so a name cannot forge a mark.
+const (
+	headlineStart         = "\uE000"
+	headlineStop          = "\uE001"
+	searchHeadlineName    = "translate(name,'" + headlineStart + headlineStop + "','')"
+	searchHeadlineOptions = "'StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", HighlightAll=true'"
+)
+
+var headlineMarks = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// highlightSnippet turns the headline of a name into HTML.
+func highlightSnippet(headline string) string {
+	return headlineMarks.Replace(html.EscapeString(headline))
+}
+
+func buildSearchQuery(params repository.SearchParams) (string, []interface{}) {
+	b := &queryBuilder{}
+	ranked := "SELECT " + articleColumns + ",query,ts_rank_cd(name_tsv,query) AS rank" +
+		" FROM articles, websearch_to_tsquery(" + searchConfig + "," + b.arg(params.Query) + ") query" +
+		" WHERE deleted_at IS NULL AND name_tsv @@ query"
+	if params.After != nil {
+		b.where("(rank<%s OR (rank=%s AND id>%s))", params.After.Rank, params.After.Rank, params.After.ID)
+	}
+	query := "SELECT " + articleColumns + ",rank," +
+		"ts_headline(" + searchConfig + "," + searchHeadlineName + ",query," + searchHeadlineOptions + ") AS snippet" +
+		" FROM (" + ranked + ") ranked" + b.whereClause() +
+		" ORDER BY rank DESC,id ASC LIMIT " + b.arg(params.Limit)
+	return query, b.args
+}
@@ -0,0 +1,60 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		params        repository.SearchParams
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{{
+		name:   "first page",
+		params: repository.SearchParams{Query: "golang", Limit: 10},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at,rank," +
+			"ts_headline('simple',translate(name,'\uE000\uE001',''),query,'StartSel=\uE000, StopSel=\uE001, HighlightAll=true') AS snippet" +
+			" FROM (SELECT id,name,rating,created_at,version,deleted_at,query,ts_rank_cd(name_tsv,query) AS rank" +
+			" FROM articles, websearch_to_tsquery('simple',$1) query WHERE deleted_at IS NULL AND name_tsv @@ query) ranked" +
+			" ORDER BY rank DESC,id ASC LIMIT $2",
+		expectedArgs: []interface{}{"golang", 10},
+	}, {
+		name:   "after cursor",
+		params: repository.SearchParams{Query: "golang", After: &repository.SearchCursor{Rank: 0.5, ID: 7}, Limit: 10},
+		expectedQuery: "SELECT id,name,rating,created_at,version,deleted_at,rank," +
+			"ts_headline('simple',translate(name,'\uE000\uE001',''),query,'StartSel=\uE000, StopSel=\uE001, HighlightAll=true') AS snippet" +
+			" FROM (SELECT id,name,rating,created_at,version,deleted_at,query,ts_rank_cd(name_tsv,query) AS rank" +
+			" FROM articles, websearch_to_tsquery('simple',$1) query WHERE deleted_at IS NULL AND name_tsv @@ query) ranked" +
+			" WHERE (rank<$2 OR (rank=$3 AND id>$4)) ORDER BY rank DESC,id ASC LIMIT $5",
+		expectedArgs: []interface{}{"golang", float32(0.5), float32(0.5), int64(7), 10},
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, args := buildSearchQuery(tc.params)
+
+			assert.Equal(t, tc.expectedQuery, query)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	t.Parallel()
+
+	// act
+	snippet := highlightSnippet("<script>alert(1)</script> in \uE000Go\uE001 & Rust")
+
+	// assert
+	assert.Equal(t, "&lt;script&gt;alert(1)&lt;/script&gt; in <mark>Go</mark> &amp; Rust", snippet)
+}
@@ -0,0 +1,63 @@
+package postgresql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+)
+
+// updateColumns is the only source of identifiers interpolated into update
+// queries, like sortColumns for listings.
+var updateColumns = map[repository.ArticleField]string{
+	repository.FieldName:   "name",
+	repository.FieldRating: "rating",
+}
+
+func fieldValue(field repository.ArticleField, article repository.Article) interface{} {
+	if field == repository.FieldRating {
+		return article.Rating
+	}
+	return article.Name
+}
+
+// buildUpdateQuery writes the fields of update and records an outbox event
+// whose payload lists under changed_fields the written fields that actually
+// got a different value, and under before the previous state, along with the
+// revision made by principal. The query returns the same columns as
+// checkedResult.
+func buildUpdateQuery(update repository.ArticleUpdate, principal string) (string, []interface{}, error) {
+	if len(update.Fields) == 0 {
+		return "", nil, errors.New("update has no fields")
+	}
+
+	b := &queryBuilder{}
+	sets := make([]string, 0, len(update.Fields))
+	changed := make([]string, 0, len(update.Fields))
+	seen := make(map[repository.ArticleField]bool, len(update.Fields))
+	for _, field := range update.Fields {
+		column, ok := updateColumns[field]
+		if !ok {
+			return "", nil, fmt.Errorf("unknown article field %d", field)
+		}
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		sets = append(sets, column+"="+b.arg(fieldValue(field, update.Article)))
+		changed = append(changed, "CASE WHEN "+column+" IS DISTINCT FROM old_"+column+" THEN '"+column+"' END")
+	}
+
+	id := b.arg(update.Article.ID)
+	version := b.arg(update.Article.Version)
+	mutation := "UPDATE articles SET " + strings.Join(sets, ",") + ",version=version+1" +
+		" FROM (SELECT id AS old_id,name AS old_name,rating AS old_rating FROM articles WHERE id=" + id + " FOR UPDATE) old" +
+		" WHERE id=old_id AND deleted_at IS NULL AND (" + version + "=0 OR version=" + version + ")" +
+		" RETURNING " + articleColumns + ",old_name,old_rating"
+	changedFields := "array_remove(ARRAY[" + strings.Join(changed, ",") + "]::text[],NULL)"
+	payload := withBefore(articlePayload, articleState("old_name", "old_rating", "version-1", "deleted_at")) +
+		"||jsonb_build_object('changed_fields'," + changedFields + ")"
+	return withRevision(mutation, repository.EventArticleUpdated, payload, changedFields, b.arg(principal),
+		checkedResult(id)), b.args, nil
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUpdateQuery(t *testing.T) {
+	t.Parallel()
+
+	article := repository.Article{ID: 7, Name: "name", Rating: 3, Version: 2}
+	testCases := []struct {
+		name          string
+		update        repository.ArticleUpdate
+		expectedSet   string
+		expectedDiff  string
+		expectedID    string
+		expectedActor string
+		expectedArgs  []interface{}
+		expectedError bool
+	}{{
+		name:          "single field",
+		update:        repository.ArticleUpdate{Article: article, Fields: []repository.ArticleField{repository.FieldRating}},
+		expectedSet:   "UPDATE articles SET rating=$1,version=version+1",
+		expectedDiff:  "ARRAY[CASE WHEN rating IS DISTINCT FROM old_rating THEN 'rating' END]",
+		expectedID:    "$2",
+		expectedActor: "$4",
+		expectedArgs:  []interface{}{int64(3), int64(7), int64(2), "editor"},
+	}, {
+		name: "duplicate fields are written once",
+		update: repository.ArticleUpdate{Article: article, Fields: []repository.ArticleField{
+			repository.FieldName, repository.FieldRating, repository.FieldName,
+		}},
+		expectedSet: "UPDATE articles SET name=$1,rating=$2,version=version+1",
+		expectedDiff: "ARRAY[CASE WHEN name IS DISTINCT FROM old_name THEN 'name' END," +
+			"CASE WHEN rating IS DISTINCT FROM old_rating THEN 'rating' END]",
+		expectedID:    "$3",
+		expectedActor: "$5",
+		expectedArgs:  []interface{}{"name", int64(3), int64(7), int64(2), "editor"},
+	}, {
+		name:          "no fields",
+		update:        repository.ArticleUpdate{Article: article},
+		expectedError: true,
+	}, {
+		name:          "unknown field",
+		update:        repository.ArticleUpdate{Article: article, Fields: []repository.ArticleField{42}},
+		expectedError: true,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, args, err := buildUpdateQuery(tc.update, "editor")
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, query, tc.expectedSet)
+			assert.Contains(t, query, tc.expectedDiff)
+			assert.Contains(t, query, "WHERE id="+tc.expectedID+" FOR UPDATE")
+			assert.Contains(t, query, checkedResult(tc.expectedID))
+			assert.Contains(t, query, "NULLIF("+tc.expectedActor+",'')")
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "context"
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the identity on whose behalf
+// mutations are made. It is recorded in the article revisions they create.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, or an empty string
+// when it is unknown.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
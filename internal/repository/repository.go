@@ -12,8 +12,18 @@ import (
 type ArticleInterface interface {
 	Create(ctx context.Context, article Article) (int64, error)
 	GetByID(ctx context.Context, id int64) (Article, error)
-	Delete(ctx context.Context, id int64) error
-	Update(ctx context.Context, article Article) error
+	Delete(ctx context.Context, id int64, expectedVersion int64) error
+	Update(ctx context.Context, update ArticleUpdate) error
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, id int64) error
+	List(ctx context.Context, params ListParams) ([]Article, error)
+	Search(ctx context.Context, params SearchParams) ([]SearchResult, error)
+	History(ctx context.Context, params HistoryParams) ([]ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64, version int64) (ArticleRevision, error)
+	BatchCreate(ctx context.Context, articles []Article, mode BatchMode) ([]BatchItemResult, error)
+	BatchUpdate(ctx context.Context, updates []ArticleUpdate, mode BatchMode) ([]BatchItemResult, error)
+	BatchDelete(ctx context.Context, ids []int64, mode BatchMode) ([]BatchItemResult, error)
+	Import(ctx context.Context, articles []Article) (ImportResult, error)
 }
 type DataBaseInterface interface {
 	GetPool() *pgxpool.Pool
@@ -21,4 +31,8 @@ type DataBaseInterface interface {
 	Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error)
 	ExecQueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row
+	BeginTx(ctx context.Context, options pgx.TxOptions) (pgx.Tx, error)
+	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columns []string, rows pgx.CopyFromSource) (int64, error)
+	RunInTx(ctx context.Context, options pgx.TxOptions, fn func(ctx context.Context) error) error
 }
@@ -2,9 +2,67 @@ package repository
 
 import "time"
 
+// Article is a stored article. Version starts at 1 and is incremented by every
+// update; when passed to Update it is the expected current version, and zero
+// skips the check. DeletedAt is set while the article is soft-deleted.
 type Article struct {
-	ID        int64     `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	Rating    int64     `db:"rating" json:"rating"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	ID        int64      `db:"id" json:"id"`
+	Name      string     `db:"name" json:"name"`
+	Rating    int64      `db:"rating" json:"rating"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	Version   int64      `db:"version" json:"version"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+// ArticleField is an article field that can be written by an update.
+type ArticleField int
+
+const (
+	FieldName ArticleField = iota + 1
+	FieldRating
+)
+
+// ArticleUpdate writes the listed Fields of Article to the article with
+// Article.ID, guarded by Article.Version like Update.
+type ArticleUpdate struct {
+	Article Article
+	Fields  []ArticleField
+}
+
+type BatchMode int
+
+const (
+	// BatchAtomic applies every item or none of them.
+	BatchAtomic BatchMode = iota
+	// BatchBestEffort applies every item that can be applied.
+	BatchBestEffort
+)
+
+// BatchItemResult is the outcome of one batch item, in request order. Err is
+// ErrBatchAborted for items rolled back because of another item.
+type BatchItemResult struct {
+	ID  int64
+	Err error
+}
+
+// ImportResult counts the outcome of one imported chunk. Duplicates are
+// articles whose name already exists, either in the table or earlier in the
+// same chunk.
+type ImportResult struct {
+	Inserted   int64
+	Duplicates int64
+}
+
+// ArticleRevision is the state of an article after the change that produced
+// Version. ChangedFields names the columns that got a different value and
+// Principal is empty when the change was made on behalf of nobody known.
+type ArticleRevision struct {
+	ArticleID     int64     `db:"article_id"`
+	Version       int64     `db:"version"`
+	Name          string    `db:"name"`
+	Rating        int64     `db:"rating"`
+	Deleted       bool      `db:"deleted"`
+	ChangedFields []string  `db:"changed_fields"`
+	Principal     string    `db:"principal"`
+	CreatedAt     time.Time `db:"created_at"`
 }
@@ -0,0 +1,55 @@
+package watcher
+
+import (
+	"fmt"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+)
+
+var eventChangeTypes = map[events.EventType]ChangeType{
+	events.EventType_EVENT_TYPE_ARTICLE_CREATED:  Created,
+	events.EventType_EVENT_TYPE_ARTICLE_UPDATED:  Updated,
+	events.EventType_EVENT_TYPE_ARTICLE_DELETED:  Deleted,
+	events.EventType_EVENT_TYPE_ARTICLE_RESTORED: Restored,
+	events.EventType_EVENT_TYPE_ARTICLE_PURGED:   Purged,
+}
+
+// EventTypes are the event types that describe article changes.
+var EventTypes = []events.EventType{
+	events.EventType_EVENT_TYPE_ARTICLE_CREATED,
+	events.EventType_EVENT_TYPE_ARTICLE_UPDATED,
+	events.EventType_EVENT_TYPE_ARTICLE_DELETED,
+	events.EventType_EVENT_TYPE_ARTICLE_RESTORED,
+	events.EventType_EVENT_TYPE_ARTICLE_PURGED,
+}
+
+// ChangeFromEvent converts an event read from the article topic into a change,
+// so that watchers observe exactly the events that were published to Kafka,
+// whichever server published them. Purges carry the article as it was before.
+func ChangeFromEvent(event *events.ArticleEvent) (Change, error) {
+	changeType, ok := eventChangeTypes[event.Type]
+	if !ok {
+		return Change{}, fmt.Errorf("event type %s is not an article change", event.Type)
+	}
+	state := event.After
+	if changeType == Purged {
+		state = event.Before
+	}
+	if state == nil {
+		return Change{}, fmt.Errorf("event %s carries no article", event.EventId)
+	}
+
+	article := repository.Article{
+		ID:        state.Id,
+		Name:      state.Name,
+		Rating:    state.Rating,
+		CreatedAt: state.CreatedAt.AsTime(),
+		Version:   state.Version,
+	}
+	if state.DeletedAt != nil {
+		deletedAt := state.DeletedAt.AsTime()
+		article.DeletedAt = &deletedAt
+	}
+	return Change{Type: changeType, Article: article, Time: event.Time.AsTime()}, nil
+}
@@ -0,0 +1,78 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/NRKA/gRPC-Server/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestChangeFromEvent(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+	state := &events.ArticleState{Id: 7, Name: "name", Rating: 10, CreatedAt: timestamppb.New(createdAt), Version: 2}
+	deletedState := &events.ArticleState{Id: 7, Name: "name", Rating: 10, CreatedAt: timestamppb.New(createdAt),
+		Version: 3, DeletedAt: timestamppb.New(deletedAt)}
+	testCases := []struct {
+		name           string
+		event          *events.ArticleEvent
+		expectedChange Change
+		expectedError  bool
+	}{{
+		name: "deleted",
+		event: &events.ArticleEvent{
+			Type:   events.EventType_EVENT_TYPE_ARTICLE_DELETED,
+			Before: state,
+			After:  deletedState,
+			Time:   timestamppb.New(deletedAt),
+		},
+		expectedChange: Change{
+			Type:    Deleted,
+			Article: repository.Article{ID: 7, Name: "name", Rating: 10, CreatedAt: createdAt, Version: 3, DeletedAt: &deletedAt},
+			Time:    deletedAt,
+		},
+	}, {
+		name: "purged",
+		event: &events.ArticleEvent{
+			Type:   events.EventType_EVENT_TYPE_ARTICLE_PURGED,
+			Before: deletedState,
+			Time:   timestamppb.New(deletedAt),
+		},
+		expectedChange: Change{
+			Type:    Purged,
+			Article: repository.Article{ID: 7, Name: "name", Rating: 10, CreatedAt: createdAt, Version: 3, DeletedAt: &deletedAt},
+			Time:    deletedAt,
+		},
+	}, {
+		name:          "not a change",
+		event:         &events.ArticleEvent{Type: events.EventType_EVENT_TYPE_ARTICLE_VIEWED, After: state},
+		expectedError: true,
+	}, {
+		name:          "no article",
+		event:         &events.ArticleEvent{Type: events.EventType_EVENT_TYPE_ARTICLE_CREATED},
+		expectedError: true,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			change, err := ChangeFromEvent(tc.event)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedChange, change)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package watcher
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+)
+
+const (
+	DefaultHistorySize = 1024
+	subscriberBuffer   = 64
+)
+
+var (
+	ErrSequenceUnavailable = errors.New("requested sequence is no longer retained")
+	ErrSubscriberTooSlow   = errors.New("subscriber fell behind and was dropped")
+)
+
+type ChangeType int
+
+const (
+	Created ChangeType = iota + 1
+	Updated
+	Deleted
+	Restored
+	Purged
+)
+
+// Change is a single article mutation. Every change carries the full article:
+// deletions as soft-deleted, purges as it was before it was removed.
+type Change struct {
+	Sequence uint64
+	Type     ChangeType
+	Article  repository.Article
+	Time     time.Time
+}
+
+// Filter selects changes for a subscription. Zero values match everything.
+// The rating bounds apply to changes of every type.
+type Filter struct {
+	IDs       map[int64]struct{}
+	MinRating *int64
+	MaxRating *int64
+}
+
+func (f Filter) Match(change Change) bool {
+	if len(f.IDs) > 0 {
+		if _, ok := f.IDs[change.Article.ID]; !ok {
+			return false
+		}
+	}
+	if f.MinRating != nil && change.Article.Rating < *f.MinRating {
+		return false
+	}
+	if f.MaxRating != nil && change.Article.Rating > *f.MaxRating {
+		return false
+	}
+	return true
+}
+
+// Hub fans article changes out to subscribers and keeps the most recent ones
+// so that a reconnecting subscriber can resume without gaps. Sequences are
+// assigned by the hub, start at 1 and are only meaningful within one process.
+type Hub struct {
+	mu          sync.Mutex
+	sequence    uint64
+	history     []Change
+	historySize int
+	subscribers map[*Subscription]struct{}
+}
+
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		history:     make([]Change, 0, historySize),
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next sequence to change and delivers it to every
+// matching subscriber. Subscribers whose buffer is full are dropped rather
+// than blocking the mutation path.
+func (h *Hub) Publish(change Change) Change {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sequence++
+	change.Sequence = h.sequence
+	if len(h.history) == h.historySize {
+		copy(h.history, h.history[1:])
+		h.history = h.history[:len(h.history)-1]
+	}
+	h.history = append(h.history, change)
+
+	for subscription := range h.subscribers {
+		if !subscription.filter.Match(change) {
+			continue
+		}
+		select {
+		case subscription.changes <- change:
+		default:
+			h.drop(subscription, ErrSubscriberTooSlow)
+		}
+	}
+	return change
+}
+
+// Subscribe registers a subscriber. When resumeAfter is not nil, retained
+// changes with a greater sequence are replayed before live ones;
+// ErrSequenceUnavailable is returned if some of them were already evicted or
+// the sequence was issued by another process.
+func (h *Hub) Subscribe(filter Filter, resumeAfter *uint64) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []Change
+	if resumeAfter != nil {
+		if *resumeAfter > h.sequence {
+			return nil, ErrSequenceUnavailable
+		}
+		if len(h.history) > 0 && *resumeAfter+1 < h.history[0].Sequence {
+			return nil, ErrSequenceUnavailable
+		}
+		for _, change := range h.history {
+			if change.Sequence > *resumeAfter && filter.Match(change) {
+				backlog = append(backlog, change)
+			}
+		}
+	}
+
+	subscription := &Subscription{
+		hub:     h,
+		filter:  filter,
+		changes: make(chan Change, len(backlog)+subscriberBuffer),
+	}
+	for _, change := range backlog {
+		subscription.changes <- change
+	}
+	h.subscribers[subscription] = struct{}{}
+	return subscription, nil
+}
+
+func (h *Hub) drop(subscription *Subscription, err error) {
+	if _, ok := h.subscribers[subscription]; !ok {
+		return
+	}
+	delete(h.subscribers, subscription)
+	subscription.err = err
+	close(subscription.changes)
+}
+
+type Subscription struct {
+	hub     *Hub
+	filter  Filter
+	changes chan Change
+	err     error
+}
+
+// Changes is closed when the subscription is dropped or closed.
+func (s *Subscription) Changes() <-chan Change {
+	return s.changes
+}
+
+// Err reports why the hub closed Changes. It is nil after Close.
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s, nil)
+}
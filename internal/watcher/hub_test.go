@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/NRKA/gRPC-Server/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, subscription *Subscription, count int) []uint64 {
+	t.Helper()
+	sequences := make([]uint64, 0, count)
+	for i := 0; i < count; i++ {
+		select {
+		case change := <-subscription.Changes():
+			sequences = append(sequences, change.Sequence)
+		default:
+			t.Fatalf("expected %d changes, got %d", count, i)
+		}
+	}
+	select {
+	case change := <-subscription.Changes():
+		t.Fatalf("unexpected change %d", change.Sequence)
+	default:
+	}
+	return sequences
+}
+
+func TestHub_Filter(t *testing.T) {
+	t.Parallel()
+
+	minRating := int64(5)
+	testCases := []struct {
+		name     string
+		filter   Filter
+		expected []uint64
+	}{{
+		name:     "everything",
+		filter:   Filter{},
+		expected: []uint64{1, 2, 3, 4},
+	}, {
+		name:     "by id",
+		filter:   Filter{IDs: map[int64]struct{}{2: {}}},
+		expected: []uint64{2, 3},
+	}, {
+		name:     "by rating applies to deletions",
+		filter:   Filter{MinRating: &minRating},
+		expected: []uint64{1, 4},
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub := NewHub(DefaultHistorySize)
+			subscription, err := hub.Subscribe(tc.filter, nil)
+			require.NoError(t, err)
+			defer subscription.Close()
+
+			hub.Publish(Change{Type: Created, Article: repository.Article{ID: 1, Rating: 10}})
+			hub.Publish(Change{Type: Created, Article: repository.Article{ID: 2, Rating: 1}})
+			hub.Publish(Change{Type: Deleted, Article: repository.Article{ID: 2, Rating: 1}})
+			hub.Publish(Change{Type: Deleted, Article: repository.Article{ID: 1, Rating: 10}})
+
+			assert.Equal(t, tc.expected, receive(t, subscription, len(tc.expected)))
+		})
+	}
+}
+
+func TestHub_Resume(t *testing.T) {
+	t.Parallel()
+
+	sequence := func(value uint64) *uint64 { return &value }
+	testCases := []struct {
+		name        string
+		resumeAfter *uint64
+		expected    []uint64
+		expectedErr error
+	}{{
+		name:     "live only",
+		expected: []uint64{},
+	}, {
+		name:        "replay retained changes",
+		resumeAfter: sequence(3),
+		expected:    []uint64{4, 5},
+	}, {
+		name:        "oldest retained change",
+		resumeAfter: sequence(2),
+		expected:    []uint64{3, 4, 5},
+	}, {
+		name:        "evicted changes",
+		resumeAfter: sequence(1),
+		expectedErr: ErrSequenceUnavailable,
+	}, {
+		name:        "sequence from the future",
+		resumeAfter: sequence(6),
+		expectedErr: ErrSequenceUnavailable,
+	},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hub := NewHub(3)
+			for i := 0; i < 5; i++ {
+				hub.Publish(Change{Type: Updated})
+			}
+
+			subscription, err := hub.Subscribe(Filter{}, tc.resumeAfter)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer subscription.Close()
+			assert.Equal(t, tc.expected, receive(t, subscription, len(tc.expected)))
+		})
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(DefaultHistorySize)
+	subscription, err := hub.Subscribe(Filter{}, nil)
+	require.NoError(t, err)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(Change{Type: Updated})
+	}
+
+	for range subscription.Changes() {
+	}
+	assert.ErrorIs(t, subscription.Err(), ErrSubscriberTooSlow)
+	subscription.Close()
+}
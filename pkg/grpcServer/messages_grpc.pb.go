@@ -20,10 +20,21 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ArticleService_CreateArticle_FullMethodName = "/ArticleService/CreateArticle"
-	ArticleService_GetArticle_FullMethodName    = "/ArticleService/GetArticle"
-	ArticleService_DeleteArticle_FullMethodName = "/ArticleService/DeleteArticle"
-	ArticleService_UpdateArticle_FullMethodName = "/ArticleService/UpdateArticle"
+	ArticleService_CreateArticle_FullMethodName       = "/ArticleService/CreateArticle"
+	ArticleService_GetArticle_FullMethodName          = "/ArticleService/GetArticle"
+	ArticleService_DeleteArticle_FullMethodName       = "/ArticleService/DeleteArticle"
+	ArticleService_RestoreArticle_FullMethodName      = "/ArticleService/RestoreArticle"
+	ArticleService_PurgeArticle_FullMethodName        = "/ArticleService/PurgeArticle"
+	ArticleService_UpdateArticle_FullMethodName       = "/ArticleService/UpdateArticle"
+	ArticleService_ListArticles_FullMethodName        = "/ArticleService/ListArticles"
+	ArticleService_SearchArticles_FullMethodName      = "/ArticleService/SearchArticles"
+	ArticleService_GetArticleHistory_FullMethodName   = "/ArticleService/GetArticleHistory"
+	ArticleService_GetArticleAtVersion_FullMethodName = "/ArticleService/GetArticleAtVersion"
+	ArticleService_WatchArticles_FullMethodName       = "/ArticleService/WatchArticles"
+	ArticleService_BatchCreateArticles_FullMethodName = "/ArticleService/BatchCreateArticles"
+	ArticleService_BatchUpdateArticles_FullMethodName = "/ArticleService/BatchUpdateArticles"
+	ArticleService_BatchDeleteArticles_FullMethodName = "/ArticleService/BatchDeleteArticles"
+	ArticleService_ImportArticles_FullMethodName      = "/ArticleService/ImportArticles"
 )
 
 // ArticleServiceClient is the client API for ArticleService service.
@@ -32,8 +43,27 @@ const (
 type ArticleServiceClient interface {
 	CreateArticle(ctx context.Context, in *CreateArticleRequest, opts ...grpc.CallOption) (*CreateArticleResponse, error)
 	GetArticle(ctx context.Context, in *GetArticleIDRequest, opts ...grpc.CallOption) (*GetArticleResponse, error)
+	// Soft-deletes an article. It is hidden from reads until it is restored and
+	// is hard-deleted once it has been deleted for longer than the retention.
 	DeleteArticle(ctx context.Context, in *DeleteArticleIDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RestoreArticle(ctx context.Context, in *RestoreArticleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Hard-deletes a soft-deleted article right away.
+	PurgeArticle(ctx context.Context, in *PurgeArticleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UpdateArticle(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListArticles(ctx context.Context, in *ListArticlesRequest, opts ...grpc.CallOption) (*ListArticlesResponse, error)
+	SearchArticles(ctx context.Context, in *SearchArticlesRequest, opts ...grpc.CallOption) (*SearchArticlesResponse, error)
+	// Lists the revisions of an article, newest first. Revisions outlive the
+	// article, so the history of a purged article remains available. Fails with
+	// NOT_FOUND for an article that never existed.
+	GetArticleHistory(ctx context.Context, in *GetArticleHistoryRequest, opts ...grpc.CallOption) (*GetArticleHistoryResponse, error)
+	GetArticleAtVersion(ctx context.Context, in *GetArticleAtVersionRequest, opts ...grpc.CallOption) (*ArticleRevision, error)
+	WatchArticles(ctx context.Context, in *WatchArticlesRequest, opts ...grpc.CallOption) (ArticleService_WatchArticlesClient, error)
+	BatchCreateArticles(ctx context.Context, in *BatchCreateArticlesRequest, opts ...grpc.CallOption) (*BatchArticlesResponse, error)
+	BatchUpdateArticles(ctx context.Context, in *BatchUpdateArticlesRequest, opts ...grpc.CallOption) (*BatchArticlesResponse, error)
+	BatchDeleteArticles(ctx context.Context, in *BatchDeleteArticlesRequest, opts ...grpc.CallOption) (*BatchArticlesResponse, error)
+	// Bulk loads articles. Rows are committed in chunks as they arrive and, unlike
+	// CreateArticle, produce no per-article events.
+	ImportArticles(ctx context.Context, opts ...grpc.CallOption) (ArticleService_ImportArticlesClient, error)
 }
 
 type articleServiceClient struct {
@@ -71,6 +101,24 @@ func (c *articleServiceClient) DeleteArticle(ctx context.Context, in *DeleteArti
 	return out, nil
 }
 
+func (c *articleServiceClient) RestoreArticle(ctx context.Context, in *RestoreArticleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ArticleService_RestoreArticle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) PurgeArticle(ctx context.Context, in *PurgeArticleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ArticleService_PurgeArticle_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *articleServiceClient) UpdateArticle(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, ArticleService_UpdateArticle_FullMethodName, in, out, opts...)
@@ -80,14 +128,162 @@ func (c *articleServiceClient) UpdateArticle(ctx context.Context, in *UpdateArti
 	return out, nil
 }
 
+func (c *articleServiceClient) ListArticles(ctx context.Context, in *ListArticlesRequest, opts ...grpc.CallOption) (*ListArticlesResponse, error) {
+	out := new(ListArticlesResponse)
+	err := c.cc.Invoke(ctx, ArticleService_ListArticles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) SearchArticles(ctx context.Context, in *SearchArticlesRequest, opts ...grpc.CallOption) (*SearchArticlesResponse, error) {
+	out := new(SearchArticlesResponse)
+	err := c.cc.Invoke(ctx, ArticleService_SearchArticles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) GetArticleHistory(ctx context.Context, in *GetArticleHistoryRequest, opts ...grpc.CallOption) (*GetArticleHistoryResponse, error) {
+	out := new(GetArticleHistoryResponse)
+	err := c.cc.Invoke(ctx, ArticleService_GetArticleHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) GetArticleAtVersion(ctx context.Context, in *GetArticleAtVersionRequest, opts ...grpc.CallOption) (*ArticleRevision, error) {
+	out := new(ArticleRevision)
+	err := c.cc.Invoke(ctx, ArticleService_GetArticleAtVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) WatchArticles(ctx context.Context, in *WatchArticlesRequest, opts ...grpc.CallOption) (ArticleService_WatchArticlesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ArticleService_ServiceDesc.Streams[0], ArticleService_WatchArticles_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &articleServiceWatchArticlesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ArticleService_WatchArticlesClient interface {
+	Recv() (*ArticleChange, error)
+	grpc.ClientStream
+}
+
+type articleServiceWatchArticlesClient struct {
+	grpc.ClientStream
+}
+
+func (x *articleServiceWatchArticlesClient) Recv() (*ArticleChange, error) {
+	m := new(ArticleChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *articleServiceClient) BatchCreateArticles(ctx context.Context, in *BatchCreateArticlesRequest, opts ...grpc.CallOption) (*BatchArticlesResponse, error) {
+	out := new(BatchArticlesResponse)
+	err := c.cc.Invoke(ctx, ArticleService_BatchCreateArticles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) BatchUpdateArticles(ctx context.Context, in *BatchUpdateArticlesRequest, opts ...grpc.CallOption) (*BatchArticlesResponse, error) {
+	out := new(BatchArticlesResponse)
+	err := c.cc.Invoke(ctx, ArticleService_BatchUpdateArticles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) BatchDeleteArticles(ctx context.Context, in *BatchDeleteArticlesRequest, opts ...grpc.CallOption) (*BatchArticlesResponse, error) {
+	out := new(BatchArticlesResponse)
+	err := c.cc.Invoke(ctx, ArticleService_BatchDeleteArticles_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) ImportArticles(ctx context.Context, opts ...grpc.CallOption) (ArticleService_ImportArticlesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ArticleService_ServiceDesc.Streams[1], ArticleService_ImportArticles_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &articleServiceImportArticlesClient{stream}
+	return x, nil
+}
+
+type ArticleService_ImportArticlesClient interface {
+	Send(*CreateArticleRequest) error
+	CloseAndRecv() (*ImportArticlesResponse, error)
+	grpc.ClientStream
+}
+
+type articleServiceImportArticlesClient struct {
+	grpc.ClientStream
+}
+
+func (x *articleServiceImportArticlesClient) Send(m *CreateArticleRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *articleServiceImportArticlesClient) CloseAndRecv() (*ImportArticlesResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportArticlesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ArticleServiceServer is the server API for ArticleService service.
 // All implementations must embed UnimplementedArticleServiceServer
 // for forward compatibility
 type ArticleServiceServer interface {
 	CreateArticle(context.Context, *CreateArticleRequest) (*CreateArticleResponse, error)
 	GetArticle(context.Context, *GetArticleIDRequest) (*GetArticleResponse, error)
+	// Soft-deletes an article. It is hidden from reads until it is restored and
+	// is hard-deleted once it has been deleted for longer than the retention.
 	DeleteArticle(context.Context, *DeleteArticleIDRequest) (*emptypb.Empty, error)
+	RestoreArticle(context.Context, *RestoreArticleRequest) (*emptypb.Empty, error)
+	// Hard-deletes a soft-deleted article right away.
+	PurgeArticle(context.Context, *PurgeArticleRequest) (*emptypb.Empty, error)
 	UpdateArticle(context.Context, *UpdateArticleRequest) (*emptypb.Empty, error)
+	ListArticles(context.Context, *ListArticlesRequest) (*ListArticlesResponse, error)
+	SearchArticles(context.Context, *SearchArticlesRequest) (*SearchArticlesResponse, error)
+	// Lists the revisions of an article, newest first. Revisions outlive the
+	// article, so the history of a purged article remains available. Fails with
+	// NOT_FOUND for an article that never existed.
+	GetArticleHistory(context.Context, *GetArticleHistoryRequest) (*GetArticleHistoryResponse, error)
+	GetArticleAtVersion(context.Context, *GetArticleAtVersionRequest) (*ArticleRevision, error)
+	WatchArticles(*WatchArticlesRequest, ArticleService_WatchArticlesServer) error
+	BatchCreateArticles(context.Context, *BatchCreateArticlesRequest) (*BatchArticlesResponse, error)
+	BatchUpdateArticles(context.Context, *BatchUpdateArticlesRequest) (*BatchArticlesResponse, error)
+	BatchDeleteArticles(context.Context, *BatchDeleteArticlesRequest) (*BatchArticlesResponse, error)
+	// Bulk loads articles. Rows are committed in chunks as they arrive and, unlike
+	// CreateArticle, produce no per-article events.
+	ImportArticles(ArticleService_ImportArticlesServer) error
 	mustEmbedUnimplementedArticleServiceServer()
 }
 
@@ -104,9 +300,42 @@ func (UnimplementedArticleServiceServer) GetArticle(context.Context, *GetArticle
 func (UnimplementedArticleServiceServer) DeleteArticle(context.Context, *DeleteArticleIDRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteArticle not implemented")
 }
+func (UnimplementedArticleServiceServer) RestoreArticle(context.Context, *RestoreArticleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreArticle not implemented")
+}
+func (UnimplementedArticleServiceServer) PurgeArticle(context.Context, *PurgeArticleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeArticle not implemented")
+}
 func (UnimplementedArticleServiceServer) UpdateArticle(context.Context, *UpdateArticleRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateArticle not implemented")
 }
+func (UnimplementedArticleServiceServer) ListArticles(context.Context, *ListArticlesRequest) (*ListArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArticles not implemented")
+}
+func (UnimplementedArticleServiceServer) SearchArticles(context.Context, *SearchArticlesRequest) (*SearchArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchArticles not implemented")
+}
+func (UnimplementedArticleServiceServer) GetArticleHistory(context.Context, *GetArticleHistoryRequest) (*GetArticleHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticleHistory not implemented")
+}
+func (UnimplementedArticleServiceServer) GetArticleAtVersion(context.Context, *GetArticleAtVersionRequest) (*ArticleRevision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticleAtVersion not implemented")
+}
+func (UnimplementedArticleServiceServer) WatchArticles(*WatchArticlesRequest, ArticleService_WatchArticlesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchArticles not implemented")
+}
+func (UnimplementedArticleServiceServer) BatchCreateArticles(context.Context, *BatchCreateArticlesRequest) (*BatchArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateArticles not implemented")
+}
+func (UnimplementedArticleServiceServer) BatchUpdateArticles(context.Context, *BatchUpdateArticlesRequest) (*BatchArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateArticles not implemented")
+}
+func (UnimplementedArticleServiceServer) BatchDeleteArticles(context.Context, *BatchDeleteArticlesRequest) (*BatchArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteArticles not implemented")
+}
+func (UnimplementedArticleServiceServer) ImportArticles(ArticleService_ImportArticlesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportArticles not implemented")
+}
 func (UnimplementedArticleServiceServer) mustEmbedUnimplementedArticleServiceServer() {}
 
 // UnsafeArticleServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -174,6 +403,42 @@ func _ArticleService_DeleteArticle_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ArticleService_RestoreArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).RestoreArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_RestoreArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).RestoreArticle(ctx, req.(*RestoreArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_PurgeArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).PurgeArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_PurgeArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).PurgeArticle(ctx, req.(*PurgeArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ArticleService_UpdateArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateArticleRequest)
 	if err := dec(in); err != nil {
@@ -192,6 +457,179 @@ func _ArticleService_UpdateArticle_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ArticleService_ListArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).ListArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_ListArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).ListArticles(ctx, req.(*ListArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_SearchArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).SearchArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_SearchArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).SearchArticles(ctx, req.(*SearchArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_GetArticleHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).GetArticleHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_GetArticleHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).GetArticleHistory(ctx, req.(*GetArticleHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_GetArticleAtVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleAtVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).GetArticleAtVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_GetArticleAtVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).GetArticleAtVersion(ctx, req.(*GetArticleAtVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_WatchArticles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchArticlesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArticleServiceServer).WatchArticles(m, &articleServiceWatchArticlesServer{stream})
+}
+
+type ArticleService_WatchArticlesServer interface {
+	Send(*ArticleChange) error
+	grpc.ServerStream
+}
+
+type articleServiceWatchArticlesServer struct {
+	grpc.ServerStream
+}
+
+func (x *articleServiceWatchArticlesServer) Send(m *ArticleChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ArticleService_BatchCreateArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).BatchCreateArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_BatchCreateArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).BatchCreateArticles(ctx, req.(*BatchCreateArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_BatchUpdateArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).BatchUpdateArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_BatchUpdateArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).BatchUpdateArticles(ctx, req.(*BatchUpdateArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_BatchDeleteArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).BatchDeleteArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_BatchDeleteArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).BatchDeleteArticles(ctx, req.(*BatchDeleteArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_ImportArticles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ArticleServiceServer).ImportArticles(&articleServiceImportArticlesServer{stream})
+}
+
+type ArticleService_ImportArticlesServer interface {
+	SendAndClose(*ImportArticlesResponse) error
+	Recv() (*CreateArticleRequest, error)
+	grpc.ServerStream
+}
+
+type articleServiceImportArticlesServer struct {
+	grpc.ServerStream
+}
+
+func (x *articleServiceImportArticlesServer) SendAndClose(m *ImportArticlesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *articleServiceImportArticlesServer) Recv() (*CreateArticleRequest, error) {
+	m := new(CreateArticleRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ArticleService_ServiceDesc is the grpc.ServiceDesc for ArticleService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -211,11 +649,58 @@ var ArticleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteArticle",
 			Handler:    _ArticleService_DeleteArticle_Handler,
 		},
+		{
+			MethodName: "RestoreArticle",
+			Handler:    _ArticleService_RestoreArticle_Handler,
+		},
+		{
+			MethodName: "PurgeArticle",
+			Handler:    _ArticleService_PurgeArticle_Handler,
+		},
 		{
 			MethodName: "UpdateArticle",
 			Handler:    _ArticleService_UpdateArticle_Handler,
 		},
+		{
+			MethodName: "ListArticles",
+			Handler:    _ArticleService_ListArticles_Handler,
+		},
+		{
+			MethodName: "SearchArticles",
+			Handler:    _ArticleService_SearchArticles_Handler,
+		},
+		{
+			MethodName: "GetArticleHistory",
+			Handler:    _ArticleService_GetArticleHistory_Handler,
+		},
+		{
+			MethodName: "GetArticleAtVersion",
+			Handler:    _ArticleService_GetArticleAtVersion_Handler,
+		},
+		{
+			MethodName: "BatchCreateArticles",
+			Handler:    _ArticleService_BatchCreateArticles_Handler,
+		},
+		{
+			MethodName: "BatchUpdateArticles",
+			Handler:    _ArticleService_BatchUpdateArticles_Handler,
+		},
+		{
+			MethodName: "BatchDeleteArticles",
+			Handler:    _ArticleService_BatchDeleteArticles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchArticles",
+			Handler:       _ArticleService_WatchArticles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportArticles",
+			Handler:       _ArticleService_ImportArticles_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/messages.proto",
 }